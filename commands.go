@@ -1,9 +1,7 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -30,6 +28,38 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 	commandArgs := strings.Split(commandBuffer, " ")
 	cmd := commandArgs[0]
 
+	if p.replay != nil {
+		return p.runReplayCommand(cmd, consoleView)
+	}
+
+	// Commands ending in ! ignore the turn phases and action rules, to correct
+	// mistakes.
+	if strings.HasSuffix(cmd, "!") {
+		cmd = strings.TrimSuffix(cmd, "!")
+		gameState.IgnoreRules = true
+		defer func() { gameState.IgnoreRules = false }()
+	}
+
+	revision := gameState.Journal.Revision()
+	actionsLeft := gameState.ActionsRemaining()
+	defer func() {
+		if gameState.Journal.Revision() == revision {
+			return
+		}
+		if gameState.IsStarted && gameState.ActionsRemaining() != actionsLeft {
+			fmt.Fprintln(consoleView, gameState.PhaseStatus())
+		}
+		for _, reason := range gameState.LossConditions() {
+			fmt.Fprintln(consoleView, p.colorOhFuck("Game lost: %v", reason))
+		}
+		for _, pending := range gameState.PendingDiscards() {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", pending))
+		}
+		if err := p.autosave(gameState); err != nil {
+			fmt.Fprintln(consoleView, p.colorOhFuck("Could not autosave: %v", err))
+		}
+	}()
+
 	curTurn, err := gameState.GameTurns.CurrentTurn()
 	if err != nil {
 		return err
@@ -47,12 +77,12 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		msg, err := gameState.Infect(cityName)
+		outbreak, err := gameState.Infect(cityName)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 		} else {
 			fmt.Fprintf(consoleView, "Infected %v.\n", cityName)
-			fmt.Fprintln(consoleView, p.colorOhFuck("%v", msg))
+			p.printOutbreak(outbreak, consoleView)
 		}
 	case "next-turn", "n":
 		turn, err := gameState.NextTurn()
@@ -110,13 +140,13 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		msg, err := gameState.Epidemic(city)
+		outbreak, err := gameState.Epidemic(city)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		} else {
 			fmt.Fprintf(consoleView, "Epidemic in %v. Please update the infect rate (infect-rate[r] N)\n", city)
-			fmt.Fprintln(consoleView, p.colorOhFuck("%v", msg))
+			p.printOutbreak(outbreak, consoleView)
 		}
 	case "infect-rate", "r":
 		if len(commandArgs) != 2 {
@@ -127,12 +157,12 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning(fmt.Sprintf("%v is not a valid infection rate", commandArgs[1])))
 		} else {
+			gameState.SetInfectionRate(int(ir))
 			fmt.Fprintf(consoleView, "infection rate now %v\n", ir)
-			gameState.InfectionRate = int(ir)
 		}
 	case "city-infect-level", "ci":
-		if len(commandArgs) != 3 {
-			fmt.Fprintln(consoleView, p.colorWarning("You must pass a city and infection value"))
+		if len(commandArgs) != 3 && len(commandArgs) != 4 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: city-infect-level[ci] <city-prefix> <infections> [disease-prefix]"))
 			break
 		}
 		il, err := strconv.ParseInt(commandArgs[2], 10, 32)
@@ -150,10 +180,19 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			fmt.Fprintln(consoleView, p.colorWarning(fmt.Sprintf("Could not get city %v: %v", cityName, err)))
 			break
 		}
-		city.SetInfections(int(il))
-		fmt.Fprintf(consoleView, "Set infection level in %v to %v\n", city.Name, city.NumInfections)
+		dt, err := diseaseArg(commandArgs, 3, city, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.SetInfections(city.Name, dt, int(il))
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "Set %v infection level in %v to %v\n", dt, city.Name, city.CubesOf(dt))
 	case "api-city-infect-level", "aci":
-		if len(commandArgs) != 3 {
+		if len(commandArgs) != 3 && len(commandArgs) != 4 {
 			break
 		}
 		il, err := strconv.ParseInt(commandArgs[2], 10, 32)
@@ -168,7 +207,11 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 		if err != nil {
 			break
 		}
-		city.SetInfections(int(il))
+		dt, err := diseaseArg(commandArgs, 3, city, gameState)
+		if err != nil {
+			break
+		}
+		gameState.SetInfections(cityName, dt, int(il))
 	case "city-draw", "c":
 		if len(commandArgs) != 2 {
 			fmt.Fprintln(consoleView, p.colorWarning("You must pass a city or funded event name to draw"))
@@ -211,12 +254,68 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		err = curPlayer.Discard(cardName)
+		holder := gameState.CardHolder(cardName)
+		if holder == nil {
+			holder = curPlayer
+		}
+		err = gameState.Discard(holder, cardName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "%v discarded %v\n", holder.HumanName, cardName)
+	case "play":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: play <event-prefix>"))
+			break
+		}
+		cardName, err := pandemic.GetCardByPrefix(commandArgs[1], gameState)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		fmt.Fprintf(consoleView, "%v discarded %v\n", curPlayer.HumanName, cardName)
+		holder := gameState.CardHolder(cardName)
+		if holder == nil {
+			holder = curPlayer
+		}
+		err = gameState.PlayEventCard(holder, cardName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "%v played %v\n", holder.HumanName, cardName)
+	case "station":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: station <city-prefix>"))
+			break
+		}
+		cityName, err := pandemic.GetCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.PlaceStation(cityName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "Placed a research station in %v, %v left\n", cityName, gameState.StationsLeft())
+	case "remove-station", "rs":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: remove-station[rs] <city-prefix>"))
+			break
+		}
+		cityName, err := pandemic.GetCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.RemoveStation(cityName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "Removed the research station from %v, %v left\n", cityName, gameState.StationsLeft())
 	case "remove-quarantine", "rq":
 		if len(commandArgs) != 2 {
 			fmt.Fprintln(consoleView, p.colorWarning("remove-quarantine must be called with a city name"))
@@ -238,12 +337,7 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorOhFuck(fmt.Sprintf("Could not create a game name folder: %v", err)))
 		}
-		data, err := json.Marshal(gameState)
-		if err != nil {
-			fmt.Fprintln(consoleView, p.colorOhFuck(fmt.Sprintf("Could not marshal gamestate as JSON: %v", err)))
-			return nil
-		}
-		err = ioutil.WriteFile(filename, data, 0644)
+		err = gameState.Save(filename)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorOhFuck(fmt.Sprintf("Could not save gamestate: %v", err)))
 			return nil
@@ -251,7 +345,7 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 		fmt.Fprintln(consoleView, "Save succefull")
 	case "treat-disease", "t":
 		if len(commandArgs) < 2 {
-			fmt.Fprintln(consoleView, p.colorWarning("treat-disease[t] <cityName> <infetions>"))
+			fmt.Fprintln(consoleView, p.colorWarning("treat-disease[t] <cityName> [infections] [disease-prefix]"))
 			return nil
 		}
 		cityName, err := pandemic.GetCityByPrefix(commandArgs[1], gameState)
@@ -265,7 +359,7 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			break
 		}
 		il := 1
-		if len(commandArgs) == 3 {
+		if len(commandArgs) >= 3 {
 			ilp, err := strconv.ParseInt(commandArgs[2], 10, 32)
 			if err != nil {
 				fmt.Fprintln(consoleView, p.colorWarning(fmt.Sprintf("%v is not a valid infection level", commandArgs[1])))
@@ -274,8 +368,17 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			il = int(ilp)
 		}
 
-		city.TreatInfections(il)
-		fmt.Fprintf(consoleView, "Treated %v infections on %v\n", il, city.Name)
+		dt, err := diseaseArg(commandArgs, 3, city, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.TreatInfections(city.Name, dt, il)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "Treated %v %v infections on %v\n", il, dt, city.Name)
 	case "player-location", "pl":
 		if len(commandArgs) != 3 {
 			fmt.Fprintln(consoleView, p.colorWarning("Usage: player-location[pl] <human-prefix> <city-prefix>"))
@@ -291,7 +394,11 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		player.SetLocation(cityName)
+		err = gameState.SetLocation(player, cityName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
 		fmt.Fprintf(consoleView, "%v new location %v\n", player.HumanName, cityName)
 	case "character-location", "cl":
 		if len(commandArgs) != 3 {
@@ -308,20 +415,113 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		player.SetLocation(cityName)
+		err = gameState.SetLocation(player, cityName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
 		fmt.Fprintf(consoleView, "%v new location %v\n", player.HumanName, cityName)
-	case "move", "m":
-		if len(commandArgs) != 2 {
-			fmt.Fprintln(consoleView, p.colorWarning("move must be called with a city name"))
-			return nil
+	case "move", "m", "drive", "fly", "charter", "shuttle", "dispatch", "pilot", "station-flight":
+		p.runMoveCommand(cmd, commandArgs, gameState, curPlayer, consoleView)
+	case "route":
+		if len(commandArgs) != 2 && len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: route [from-city-prefix] <to-city-prefix>"))
+			break
 		}
-		cityName, err := pandemic.GetCityByPrefix(commandArgs[1], gameState)
+		from := curPlayer.Location
+		if len(commandArgs) == 3 {
+			if from, err = pandemic.GetCityByPrefix(commandArgs[1], gameState); err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+				break
+			}
+		}
+		to, err := pandemic.GetCityByPrefix(commandArgs[len(commandArgs)-1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		route, err := gameState.PlanRoute(curPlayer, from, to)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintln(consoleView, route)
+		fmt.Fprintf(consoleView, "%v connections by road\n", gameState.Board().Distance(from, to))
+	case "chains":
+		if len(commandArgs) > 1 {
+			city, err := gameState.Board().CityByPrefix(commandArgs[1])
+			if err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+				break
+			}
+			cascade, err := gameState.PredictCascade(city.Name, city.Disease)
+			if err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+				break
+			}
+			risk := pandemic.ChainRisk{Cascade: cascade, Probability: gameState.InfectionOdds(city.Name).Outbreak}
+			fmt.Fprintln(consoleView, p.describeChainRisk(risk))
+			break
+		}
+		risks := gameState.ChainRisks()
+		if len(risks) == 0 {
+			fmt.Fprintln(consoleView, p.colorAllGood("No outbreaks possible this turn"))
+		}
+		for i, risk := range risks {
+			if i == 10 {
+				break
+			}
+			fmt.Fprintln(consoleView, p.describeChainRisk(risk))
+		}
+	case "build":
+		err := gameState.BuildStation(curPlayer)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "%v built a research station in %v\n", curPlayer.HumanName, curPlayer.Location)
+	case "treat":
+		city, err := gameState.GetCity(curPlayer.Location)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		curPlayer.SetLocation(cityName)
-		fmt.Fprintf(consoleView, "%v moved to %v\n", curPlayer.HumanName, cityName)
+		dt, err := diseaseArg(commandArgs, 1, city, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.Treat(curPlayer, dt)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "%v treated %v in %v, %v cubes left\n", curPlayer.HumanName, dt, city.Name, city.CubesOf(dt))
+	case "take":
+		if len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: take <human-prefix> <city-prefix>"))
+			break
+		}
+		from, err := pandemic.GetPlayerByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		if from == nil {
+			fmt.Fprintln(consoleView, p.colorWarning("Player with prefix '%v' not found", commandArgs[1]))
+			break
+		}
+		cardName, err := pandemic.GetCardByPrefix(commandArgs[2], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.ExchangeCard(from, curPlayer, cardName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "%v took %v from %v\n", curPlayer.HumanName, cardName, from.HumanName)
 	case "start-city-draw", "sc":
 		if len(commandArgs) != 2 {
 			fmt.Fprintln(consoleView, p.colorWarning("You must pass a city or funded event name to draw"))
@@ -338,8 +538,95 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 			break
 		}
 		fmt.Fprintf(consoleView, "%v drew %v from city deck\n", curPlayer.HumanName, cardName)
+	case "cure":
+		if len(commandArgs) < 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: cure <disease-prefix> [card-prefix...]"))
+			break
+		}
+		dt, err := pandemic.GetDiseaseByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		cards := []pandemic.CardName{}
+		for _, prefix := range commandArgs[2:] {
+			cardName, err := pandemic.GetCardByPrefix(prefix, gameState)
+			if err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+				break
+			}
+			cards = append(cards, cardName)
+		}
+		if len(cards) != len(commandArgs[2:]) {
+			break
+		}
+		err = gameState.Cure(curPlayer, dt, cards)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("Could not cure %v: %v", dt, err))
+			break
+		}
+		fmt.Fprintf(consoleView, "%v cured %v\n", curPlayer.HumanName, dt)
+		if gameState.IsEradicated(dt) {
+			fmt.Fprintf(consoleView, "%v is eradicated\n", dt)
+		}
+	case "panic", "p":
+		if len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: panic[p] <city-prefix> <level>"))
+			break
+		}
+		cityName, err := pandemic.GetCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		level, err := pandemic.GetPanicLevelByPrefix(commandArgs[2])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.SetPanicLevel(cityName, level)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "%v is now %v\n", cityName, level)
+	case "phase":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: phase <actions|draw|infect|done>"))
+			break
+		}
+		phase, err := pandemic.GetTurnPhaseByPrefix(commandArgs[1])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.SetPhase(phase)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintln(consoleView, gameState.PhaseStatus())
 	case "undo", "u":
-		break
+		ev, err := gameState.Undo()
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("Could not undo: %v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "Undid %v\n", ev)
+	case "redo":
+		ev, outbreak, err := gameState.Redo()
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("Could not redo: %v", err))
+			break
+		}
+		fmt.Fprintf(consoleView, "Redid %v\n", ev)
+		p.printOutbreak(outbreak, consoleView)
+	case "end-month", "lose-character", "scar":
+		if p.campaign == nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v is only available when playing a --campaign", cmd))
+			break
+		}
+		p.runCampaignCommand(cmd, commandArgs, gameState, consoleView)
 	case "start":
 		gameState.StartGame()
 		fmt.Fprintf(consoleView, "Game started\n")
@@ -357,14 +644,37 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 		fmt.Fprintln(consoleView, "")
 		fmt.Fprintln(consoleView, "give-card               g")
 		fmt.Fprintln(consoleView, "discard                 d")
+		fmt.Fprintln(consoleView, "play")
 		fmt.Fprintln(consoleView, "quarantine              q")
 		fmt.Fprintln(consoleView, "remove-quarantine       rq")
+		fmt.Fprintln(consoleView, "panic                   p")
+		fmt.Fprintln(consoleView, "station")
+		fmt.Fprintln(consoleView, "remove-station          rs")
+		fmt.Fprintln(consoleView, "cure")
 		fmt.Fprintln(consoleView, "")
 		fmt.Fprintln(consoleView, "move                    m")
+		fmt.Fprintln(consoleView, "drive|fly|charter|shuttle|dispatch|pilot")
+		fmt.Fprintln(consoleView, "station-flight")
+		fmt.Fprintln(consoleView, "route")
+		fmt.Fprintln(consoleView, "chains")
+		fmt.Fprintln(consoleView, "build")
+		fmt.Fprintln(consoleView, "treat")
+		fmt.Fprintln(consoleView, "take")
 		fmt.Fprintln(consoleView, "next-turn               n")
+		fmt.Fprintln(consoleView, "phase")
 		fmt.Fprintln(consoleView, "")
 		fmt.Fprintln(consoleView, "player-location         l")
 		fmt.Fprintln(consoleView, "save                    s")
+		fmt.Fprintln(consoleView, "undo                    u")
+		fmt.Fprintln(consoleView, "redo")
+		fmt.Fprintln(consoleView, "")
+		fmt.Fprintln(consoleView, "End a command with ! to ignore the turn phase and action rules, e.g. i! atlanta")
+		if p.campaign != nil {
+			fmt.Fprintln(consoleView, "")
+			fmt.Fprintln(consoleView, "end-month <win|loss>")
+			fmt.Fprintln(consoleView, "lose-character <character-prefix>")
+			fmt.Fprintln(consoleView, "scar <character-prefix> <scar>")
+		}
 
 	default:
 		fmt.Fprintln(consoleView, p.colorWarning(fmt.Sprintf("Unrecognized command %v", cmd)))
@@ -373,3 +683,173 @@ func (p *PandemicView) runStaticCommand(commandBuffer string, gameState *pandemi
 
 	return nil
 }
+
+// describeChainRisk tells how likely a city is to outbreak this turn and
+// what the outbreak would chain into.
+func (p *PandemicView) describeChainRisk(risk pandemic.ChainRisk) string {
+	chain := ""
+	if len(risk.Chained) > 0 {
+		names := []string{}
+		for _, cn := range risk.Chained {
+			names = append(names, string(cn))
+		}
+		chain = fmt.Sprintf(", chaining into %v", strings.Join(names, " "))
+	}
+	return fmt.Sprintf("%v %.2f: %v outbreaks, %v %v cubes spilled%v (expected %.2f outbreaks, %.2f cubes)", risk.Origin, risk.Probability,
+		risk.Outbreaks(), risk.Cubes, risk.Disease, chain, risk.ExpectedOutbreaks(), risk.ExpectedCubes())
+}
+
+var moveCommands = map[string]pandemic.ActionType{
+	"drive":          pandemic.DriveAction,
+	"fly":            pandemic.DirectFlightAction,
+	"charter":        pandemic.CharterFlightAction,
+	"shuttle":        pandemic.ShuttleFlightAction,
+	"dispatch":       pandemic.DispatchAction,
+	"pilot":          pandemic.PilotFlightAction,
+	"station-flight": pandemic.StationFlightAction,
+}
+
+// runMoveCommand moves a pawn with the action named by the command, or with
+// whichever legal move there is for move. The current player moves their
+// own pawn unless another player is named.
+func (p *PandemicView) runMoveCommand(cmd string, commandArgs []string, gameState *pandemic.GameState, curPlayer *pandemic.Player, consoleView *gocui.View) {
+	action := moveCommands[cmd]
+	if len(commandArgs) != 2 && len(commandArgs) != 3 {
+		if action == pandemic.StationFlightAction {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: station-flight <city-prefix> <card-prefix>"))
+		} else {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: %v <city-prefix> [human-prefix]", cmd))
+		}
+		return
+	}
+	cityName, err := pandemic.GetCityByPrefix(commandArgs[1], gameState)
+	if err != nil {
+		fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		return
+	}
+	pawn := curPlayer
+	var card pandemic.CardName
+	if len(commandArgs) == 3 && action == pandemic.StationFlightAction {
+		if card, err = pandemic.GetCardByPrefix(commandArgs[2], gameState); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return
+		}
+	} else if len(commandArgs) == 3 {
+		if pawn, err = pandemic.GetPlayerByPrefix(commandArgs[2], gameState); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return
+		}
+		if pawn == nil {
+			fmt.Fprintln(consoleView, p.colorWarning("Player with prefix '%v' not found", commandArgs[2]))
+			return
+		}
+	}
+	err = gameState.MovePawn(pawn, cityName, action, card)
+	if err != nil {
+		fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		return
+	}
+	fmt.Fprintf(consoleView, "%v moved to %v\n", pawn.HumanName, cityName)
+}
+
+// diseaseArg reads an optional disease prefix from the command arguments,
+// defaulting to the city's own disease.
+func diseaseArg(commandArgs []string, index int, city *pandemic.City, gameState *pandemic.GameState) (pandemic.DiseaseType, error) {
+	if len(commandArgs) <= index {
+		return city.Disease, nil
+	}
+	return pandemic.GetDiseaseByPrefix(commandArgs[index], gameState)
+}
+
+// printOutbreak prints an outbreak cascade, one neighbor per line. Nothing
+// is printed if there was no outbreak.
+func (p *PandemicView) printOutbreak(outbreak *pandemic.OutbreakResult, consoleView *gocui.View) {
+	if outbreak == nil {
+		return
+	}
+	for _, line := range outbreak.Lines() {
+		fmt.Fprintln(consoleView, p.colorOhFuck("%v", line))
+	}
+}
+
+// autosave persists the game into its autosave file. Nothing is written
+// until the game has changed at least once.
+func (p *PandemicView) autosave(gameState *pandemic.GameState) error {
+	if p.autosaveFile == "" || gameState.Journal.Revision() == 0 {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(p.autosaveFile), 0755)
+	if err != nil {
+		return err
+	}
+	return gameState.Save(p.autosaveFile)
+}
+
+// runCampaignCommand records campaign results for the month being played
+// and saves the campaign file after every change.
+func (p *PandemicView) runCampaignCommand(cmd string, commandArgs []string, gameState *pandemic.GameState, consoleView *gocui.View) {
+	switch cmd {
+	case "end-month":
+		if len(commandArgs) != 2 || (commandArgs[1] != "win" && commandArgs[1] != "loss") {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: end-month <win|loss>"))
+			return
+		}
+		result := p.campaign.EndMonth(gameState, commandArgs[1] == "win")
+		fmt.Fprintf(consoleView, "Recorded %v with %v outbreaks and %v faded cities\n", result.Month, result.Outbreaks, len(result.FadedCities))
+		if next, err := p.campaign.NextMonth(); err == nil {
+			fmt.Fprintf(consoleView, "Next month: %v\n", next)
+		} else {
+			fmt.Fprintln(consoleView, p.colorAllGood("%v", err))
+		}
+	case "lose-character", "scar":
+		if len(commandArgs) < 2 || (cmd == "scar" && len(commandArgs) < 3) {
+			fmt.Fprintln(consoleView, p.colorWarning("Usage: lose-character <character-prefix> or scar <character-prefix> <scar>"))
+			return
+		}
+		player, err := pandemic.GetPlayerByCharacter(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return
+		}
+		if cmd == "scar" {
+			scar := strings.Join(commandArgs[2:], " ")
+			p.campaign.Scar(player.Character.Type, scar)
+			fmt.Fprintf(consoleView, "%v is scarred: %v\n", player.Character.Type, scar)
+		} else {
+			p.campaign.LoseCharacter(player.Character.Type)
+			fmt.Fprintf(consoleView, "%v is lost\n", player.Character.Type)
+		}
+	}
+	if err := p.campaign.Save(p.campaignFile); err != nil {
+		fmt.Fprintln(consoleView, p.colorOhFuck("Could not save campaign: %v", err))
+	}
+}
+
+// runReplayCommand handles the commands available while stepping through a
+// saved game. The replayed state is read-only.
+func (p *PandemicView) runReplayCommand(cmd string, consoleView *gocui.View) error {
+	switch cmd {
+	case "step", "next-turn", "n":
+		steps, err := p.replay.StepTurn()
+		for _, step := range steps {
+			fmt.Fprintf(consoleView, "%v\n", step.Event)
+			p.printOutbreak(step.Outbreak, consoleView)
+		}
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		if p.replay.Done() {
+			fmt.Fprintln(consoleView, p.colorAllGood("End of the saved game"))
+		} else {
+			fmt.Fprintf(consoleView, "%v events remaining\n", p.replay.Remaining())
+		}
+	case "help", "h":
+		fmt.Fprintln(consoleView, "Replay")
+		fmt.Fprintln(consoleView, "")
+		fmt.Fprintln(consoleView, "step                    n")
+	default:
+		fmt.Fprintln(consoleView, p.colorWarning(fmt.Sprintf("%v is not available while replaying, use step[n]", cmd)))
+	}
+	return nil
+}
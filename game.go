@@ -1,10 +1,18 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gmsconstantino/pandemic-nerd-hurd/pandemic"
+	"github.com/gmsconstantino/pandemic-nerd-hurd/pandemic/sim"
 	"github.com/sirupsen/logrus"
 	"github.com/jroimartin/gocui"
 
@@ -15,38 +23,76 @@ import (
 	"github.com/gorilla/mux"
 )
 
+var months = []string{
+	"jan",
+	"feb",
+	"mar",
+	"apr",
+	"may",
+	"jun",
+	"jul",
+	"aug",
+	"sep",
+	"oct",
+	"nov",
+	"dec",
+	"jan2",
+	"feb2",
+	"mar2",
+	"apr2",
+	"may2",
+	"jun2",
+	"jul2",
+	"aug2",
+	"sep2",
+	"oct2",
+	"nov2",
+	"dec2",
+}
+
 var (
 	app              = kingpin.New("pandemic–nerd-hurd", "Start a nerd herd game")
 	startCmd         = app.Command("start", "Start a new game")
 	startNewGameFile = startCmd.Flag("new-game-file", "The file containing initial data about Cities, Players and Funded Events.").Default("data/new_game.json").ExistingFile()
-	startMonth       = startCmd.Flag("month", "The name of the month in the game we are playing. If playing the second time in a month, add '2' after the name").Required().Enum(
-		"jan",
-		"feb",
-		"mar",
-		"apr",
-		"may",
-		"jun",
-		"jul",
-		"aug",
-		"sep",
-		"oct",
-		"nov",
-		"dec",
-		"jan2",
-		"feb2",
-		"mar2",
-		"apr2",
-		"may2",
-		"jun2",
-		"jul2",
-		"aug2",
-		"sep2",
-		"oct2",
-		"nov2",
-		"dec2",
-	)
+	startMonth       = startCmd.Flag("month", "The name of the month in the game we are playing. If playing the second time in a month, add '2' after the name. Defaults to the next month of the campaign").Enum(months...)
+	startCampaign    = startCmd.Flag("campaign", "A campaign file to derive the month's settings from, instead of the new game file").ExistingFile()
 	loadCmd  = app.Command("load", "Load a game from an existing saved game")
 	loadFile = loadCmd.Flag("file", "The JSON file containing the game state").Required().ExistingFile()
+
+	replayCmd  = app.Command("replay", "Step through a saved game turn by turn")
+	replayFile = replayCmd.Flag("file", "The JSON file containing the saved game").Required().ExistingFile()
+
+	resumeCmd      = app.Command("resume", "Continue the most recent autosave of a month")
+	resumeMonth    = resumeCmd.Flag("month", "The name of the month to resume").Required().Enum(months...)
+	resumeCampaign = resumeCmd.Flag("campaign", "The campaign file the month belongs to").ExistingFile()
+
+	campaignCmd         = app.Command("campaign", "Track a Legacy campaign across months")
+	campaignInitCmd     = campaignCmd.Command("init", "Start a new campaign from a new game file")
+	campaignInitFile    = campaignInitCmd.Flag("file", "The campaign file to create").Default("campaign.json").String()
+	campaignNewGameFile = campaignInitCmd.Flag("new-game-file", "The file containing initial data about Cities, Players and Funded Events.").Default("data/legacy1.json").ExistingFile()
+	campaignShowCmd     = campaignCmd.Command("show", "Show the results of every month of a campaign")
+	campaignShowFile    = campaignShowCmd.Flag("file", "The campaign file").Default("campaign.json").ExistingFile()
+
+	setupCmd             = app.Command("setup", "Choose the funded events of a month and check them against the funding level")
+	setupNewGameFile     = setupCmd.Flag("new-game-file", "The file containing initial data about Cities, Players and Funded Events.").Default("data/new_game.json").ExistingFile()
+	setupCampaign        = setupCmd.Flag("campaign", "A campaign file to choose the next month's funded events for, instead of the new game file").ExistingFile()
+	setupFundingLevelSet bool
+	setupFundingLevel    = setupCmd.Flag("funding-level", "The funding level of the month. Defaults to the level in the new game file or campaign").IsSetByUser(&setupFundingLevelSet).Int()
+	setupFundedEvents    = setupCmd.Flag("funded-event", "A funded event to shuffle into the city deck, repeat once per event").Strings()
+	setupOutput          = setupCmd.Flag("output", "Write the checked new game settings to this file").String()
+
+	validateCmd  = app.Command("validate", "Check a new game file for mistakes in the board, players and deck")
+	validateFile = validateCmd.Flag("file", "The new game file to check").Default("data/new_game.json").ExistingFile()
+
+	simulateCmd        = app.Command("simulate", "Play a saved game forward many times to estimate the risks of the coming turns")
+	simulateFile       = simulateCmd.Flag("file", "The JSON file containing the saved game").Required().ExistingFile()
+	simulateTurns      = simulateCmd.Flag("turns", "The number of turns to play forward").Default("3").Int()
+	simulateIterations = simulateCmd.Flag("iterations", "The number of games to simulate").Default("1000").Int()
+	simulateSeedSet    bool
+	simulateSeed       = simulateCmd.Flag("seed", "The seed of the random cards, to repeat a simulation. Defaults to the current time").IsSetByUser(&simulateSeedSet).Int64()
+
+	migrateCmd  = app.Command("migrate", "Upgrade a saved game to the current schema, keeping a backup")
+	migrateFile = migrateCmd.Flag("file", "The JSON file containing the saved game").Required().ExistingFile()
 )
 
 func main() {
@@ -58,10 +104,116 @@ func main() {
 	wd, _ := os.Getwd()
 
 	var gameState *pandemic.GameState
+	var replay *pandemic.Replay
+	var autosaveFile string
+	var campaign *pandemic.Campaign
+	var campaignFile string
 
 	switch cmd {
+	case "migrate":
+		version, backup, err := pandemic.MigrateFile(*migrateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not migrate %v: %v\n", *migrateFile, err)
+			os.Exit(1)
+		}
+		if backup == "" {
+			fmt.Printf("%v is already at schema version %v\n", *migrateFile, version)
+		} else {
+			fmt.Printf("Migrated %v from schema version %v to %v, original kept in %v\n", *migrateFile, version, pandemic.SchemaVersion, backup)
+		}
+		return
+	case "validate":
+		warnings, err := pandemic.ValidateNewGameFile(*validateFile)
+		printWarnings(warnings)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v is not valid:\n%v\n", *validateFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%v is valid\n", *validateFile)
+		return
+	case "simulate":
+		if err := simulate(); err != nil {
+			app.Fatalf("%v", err)
+		}
+		return
+	case "setup":
+		if err := setup(); err != nil {
+			app.Fatalf("%v", err)
+		}
+		return
+	case "campaign init":
+		if _, err := os.Stat(*campaignInitFile); err == nil {
+			app.Fatalf("%v already exists", *campaignInitFile)
+		}
+		campaign, err := pandemic.NewCampaign(*campaignNewGameFile)
+		if err != nil {
+			app.Fatalf("%v", err)
+		}
+		if err := campaign.Save(*campaignInitFile); err != nil {
+			app.Fatalf("Could not save campaign: %v", err)
+		}
+		fmt.Printf("Created campaign %v from %v\n", *campaignInitFile, *campaignNewGameFile)
+		return
+	case "campaign show":
+		campaign, err := pandemic.LoadCampaign(*campaignShowFile)
+		if err != nil {
+			app.Fatalf("%v", err)
+		}
+		printCampaign(campaign)
+		return
 	case "start":
-		gameState, err = pandemic.NewGame(filepath.Join(wd, *startNewGameFile), *startMonth)
+		month := *startMonth
+		var settings *pandemic.NewGameSettings
+		if *startCampaign != "" {
+			campaignFile = *startCampaign
+			campaign, err = pandemic.LoadCampaign(campaignFile)
+			if err != nil {
+				app.Fatalf("%v", err)
+			}
+			if month == "" {
+				month, err = campaign.NextMonth()
+				if err != nil {
+					app.Fatalf("%v", err)
+				}
+			}
+			settings, err = campaign.NextSettings()
+			if err != nil {
+				app.Fatalf("%v", err)
+			}
+		}
+		if month == "" {
+			app.Fatalf("--month is required when not playing a --campaign")
+		}
+		if latest, err := pandemic.LatestAutosave(month); err == nil && promptResume(latest) {
+			gameState, err = pandemic.LoadGame(filepath.Join(wd, latest))
+			if err != nil {
+				logger.Fatalln(err)
+			}
+			autosaveFile = latest
+			break
+		}
+		if settings != nil {
+			gameState, err = pandemic.NewGameFromSettings(settings, month)
+		} else {
+			gameState, err = pandemic.NewGame(filepath.Join(wd, *startNewGameFile), month)
+		}
+		if err != nil {
+			logger.Fatalln(err)
+		}
+		autosaveFile = pandemic.AutosaveFile(gameState.GameName, time.Now())
+	case "resume":
+		if *resumeCampaign != "" {
+			campaignFile = *resumeCampaign
+			campaign, err = pandemic.LoadCampaign(campaignFile)
+			if err != nil {
+				app.Fatalf("%v", err)
+			}
+		}
+		autosaveFile, err = pandemic.LatestAutosave(*resumeMonth)
+		if err != nil {
+			logger.Fatalln(err)
+		}
+		gameState, err = pandemic.LoadGame(filepath.Join(wd, autosaveFile))
 		if err != nil {
 			logger.Fatalln(err)
 		}
@@ -70,13 +222,24 @@ func main() {
 		if err != nil {
 			logger.Fatalln(err)
 		}
+		autosaveFile = pandemic.AutosaveFile(gameState.GameName, time.Now())
+	case "replay":
+		replay, err = pandemic.LoadReplay(filepath.Join(wd, *replayFile))
+		if err != nil {
+			logger.Fatalln(err)
+		}
+		gameState = replay.State
 	}
 
 	view := NewView(logger)
+	view.replay = replay
+	view.autosaveFile = autosaveFile
+	view.campaign = campaign
+	view.campaignFile = campaignFile
 	gui, err := gocui.NewGui(gocui.OutputNormal)
 
 	if err != nil {
-		view.logger.Errorln("Could not init GUI: %v", err)
+		view.logger.Errorf("Could not init GUI: %v", err)
 	}
 	defer gui.Close()
 
@@ -93,3 +256,181 @@ func main() {
 	view.Start(gameState, gui)
 
 }
+
+// setup checks the funded events of a month against its funding level. With
+// a campaign the chosen events are stored for its next month, otherwise the
+// checked settings can be written out to use with start --new-game-file.
+func setup() error {
+	names := []pandemic.FundedEventName{}
+	for _, name := range *setupFundedEvents {
+		names = append(names, pandemic.FundedEventName(name))
+	}
+
+	if *setupCampaign != "" {
+		campaign, err := pandemic.LoadCampaign(*setupCampaign)
+		if err != nil {
+			return err
+		}
+		if setupFundingLevelSet {
+			campaign.FundingLevel = *setupFundingLevel
+		}
+		if err := campaign.ChooseFundedEvents(names); err != nil {
+			printFundedEventCatalogue()
+			return err
+		}
+		settings := pandemic.NewGameSettings{FundedEvents: campaign.FundedEvents}
+		printWarnings(settings.FundingWarnings())
+		if err := campaign.Save(*setupCampaign); err != nil {
+			return err
+		}
+		printFundedEvents(campaign.FundingLevel, campaign.FundedEvents)
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(*setupNewGameFile)
+	if err != nil {
+		return err
+	}
+	var settings pandemic.NewGameSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("Invalid new game JSON file at %v: %v", *setupNewGameFile, err)
+	}
+	if setupFundingLevelSet {
+		settings.FundingLevel = setupFundingLevel
+	}
+	if len(names) > 0 {
+		settings.FundedEvents = []*pandemic.FundedEvent{}
+		for _, name := range names {
+			settings.FundedEvents = append(settings.FundedEvents, pandemic.NamedFundedEvent(name))
+		}
+	}
+	printWarnings(settings.FundingWarnings())
+	if err := settings.ValidateFunding(); err != nil {
+		printFundedEventCatalogue()
+		return err
+	}
+	level := len(settings.FundedEvents)
+	if settings.FundingLevel != nil {
+		level = *settings.FundingLevel
+	}
+	printFundedEvents(level, settings.FundedEvents)
+	if *setupOutput == "" {
+		return nil
+	}
+	out, err := json.MarshalIndent(settings, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(*setupOutput, out, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %v\n", *setupOutput)
+	return nil
+}
+
+// simulate plays a saved game forward and prints how often it was lost,
+// the outbreaks and cubes it took, and the cities most likely to outbreak.
+func simulate() error {
+	gameState, err := pandemic.LoadGame(*simulateFile)
+	if err != nil {
+		return err
+	}
+	seed := *simulateSeed
+	if !simulateSeedSet {
+		seed = time.Now().UnixNano()
+	}
+	result, err := sim.Run(gameState, sim.Options{Turns: *simulateTurns, Iterations: *simulateIterations, Seed: seed})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Simulated %v games of %v turns of %v with seed %v\n", result.Iterations, result.Turns, gameState.GameName, seed)
+	reasons := []string{}
+	for reason, count := range result.LossReasons {
+		reasons = append(reasons, fmt.Sprintf("%v %.1f%%", reason, 100*float64(count)/float64(result.Iterations)))
+	}
+	sort.Strings(reasons)
+	if len(reasons) > 0 {
+		fmt.Printf("Lost %.1f%% (%v)\n", 100*result.LossProbability(), strings.Join(reasons, ", "))
+	} else {
+		fmt.Println("Lost none")
+	}
+	fmt.Printf("%-16v mean %.2f, median %v, 90th percentile %v, max %v\n", "Outbreaks", result.Outbreaks.Mean(),
+		result.Outbreaks.Percentile(0.5), result.Outbreaks.Percentile(0.9), result.Outbreaks.Max())
+	for _, dt := range result.Diseases() {
+		cubes := result.Cubes[dt]
+		fmt.Printf("%-16v mean %.2f, median %v, 90th percentile %v, max %v\n", fmt.Sprintf("%v cubes", dt), cubes.Mean(),
+			cubes.Percentile(0.5), cubes.Percentile(0.9), cubes.Max())
+	}
+	cities := result.RiskiestCities()
+	if len(cities) == 0 {
+		return nil
+	}
+	fmt.Println("Cities most likely to outbreak:")
+	for i, cn := range cities {
+		if i == 10 {
+			break
+		}
+		fmt.Printf("  %-24v %.1f%%\n", cn, 100*result.OutbreakRisk(cn))
+	}
+	return nil
+}
+
+func printFundedEvents(level int, events []*pandemic.FundedEvent) {
+	fmt.Printf("Funding level %v\n", level)
+	for _, event := range events {
+		effect := event.Effect
+		if known, err := pandemic.LookupFundedEvent(event.Name); err == nil {
+			effect = known.Effect
+		}
+		fmt.Printf("  %-24v %v\n", event.Name, effect)
+	}
+}
+
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+}
+
+func printFundedEventCatalogue() {
+	fmt.Println("Known funded events:")
+	for _, event := range pandemic.FundedEventCatalogue() {
+		fmt.Printf("  %-24v %v\n", event.Name, event.Effect)
+	}
+}
+
+func printCampaign(campaign *pandemic.Campaign) {
+	for _, month := range campaign.Months {
+		result := "lost"
+		if month.Won {
+			result = "won"
+		}
+		fmt.Printf("%-5v %-4v funding %v, outbreaks %v, %v cities panicking, %v faded cities", month.Month, result, month.FundingLevel, month.Outbreaks, len(month.PanicLevels), len(month.FadedCities))
+		if len(month.CharactersLost) > 0 {
+			fmt.Printf(", lost %v", month.CharactersLost)
+		}
+		fmt.Println()
+		for character, scars := range month.Scars {
+			fmt.Printf("      %v scarred: %v\n", character, strings.Join(scars, ", "))
+		}
+	}
+	if next, err := campaign.NextMonth(); err == nil {
+		fmt.Printf("Next month: %v at funding level %v\n", next, campaign.FundingLevel)
+	} else {
+		fmt.Println(err)
+	}
+}
+
+// promptResume asks on the terminal, before the GUI starts, whether an
+// existing autosave should be continued instead of starting over.
+func promptResume(autosave string) bool {
+	modified := ""
+	if info, err := os.Stat(autosave); err == nil {
+		modified = info.ModTime().Format("Jan 2 15:04")
+	}
+	fmt.Printf("Found autosave %v (%v). Resume it? [y/N] ", autosave, modified)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -3,16 +3,19 @@ module github.com/gmsconstantino/pandemic-nerd-hurd
 go 1.22.6
 
 require (
-	github.com/alecthomas/kingpin/v2 v2.4.0 // indirect
+	github.com/alecthomas/kingpin/v2 v2.4.0
+	github.com/fatih/color v1.17.0
+	github.com/gorilla/mux v1.8.1
+	github.com/jroimartin/gocui v0.5.0
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require (
 	github.com/alecthomas/units v0.0.0-20211218093645-b94a6e3cc137 // indirect
-	github.com/fatih/color v1.17.0 // indirect
-	github.com/gorilla/mux v1.8.1 // indirect
-	github.com/jroimartin/gocui v0.5.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mattn/go-runewidth v0.0.9 // indirect
 	github.com/nsf/termbox-go v1.1.1 // indirect
-	github.com/sirupsen/logrus v1.9.3 // indirect
 	github.com/xhit/go-str2duration/v2 v2.1.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
 )
@@ -0,0 +1,190 @@
+package pandemic
+
+const (
+	// ActionsPerTurn is the number of actions a character without abilities
+	// takes on their turn.
+	ActionsPerTurn = 4
+	// HandLimit is the number of cards a character without abilities can
+	// hold at the end of a draw.
+	HandLimit = 7
+)
+
+// Ability is one way a character bends the rules.
+type Ability string
+
+const (
+	// Passive abilities, applied by the rules.
+	TreatsCuredOnEntry Ability = "treats-cured-on-entry"
+	PreventsCuredCubes Ability = "prevents-cured-cubes"
+	TreatsAllCubes     Ability = "treats-all-cubes"
+	HoldsQuarantines   Ability = "holds-quarantines"
+	ContainsOnEntry    Ability = "contains-on-entry"
+	// Movement abilities.
+	MovesOtherPawns  Ability = "moves-other-pawns"
+	FliesWithinTwo   Ability = "flies-within-two"
+	FliesFromStation Ability = "flies-from-station"
+	// Actions only the character can take.
+	BuildsWithoutCard  Ability = "builds-without-card"
+	GivesAnyCityCard   Ability = "gives-any-city-card"
+	TakesCardFromOther Ability = "takes-card-from-other"
+	StoresEventCard    Ability = "stores-event-card"
+	RecoversCityCard   Ability = "recovers-city-card"
+	PeeksInfectionDeck Ability = "peeks-infection-deck"
+	CollectsSamples    Ability = "collects-samples"
+)
+
+var abilityDescriptions = map[Ability]string{
+	TreatsCuredOnEntry: "Removes all cubes of cured diseases from the cities they enter",
+	PreventsCuredCubes: "Cubes of cured diseases cannot be placed in their city",
+	TreatsAllCubes:     "Treating removes all cubes of the disease",
+	HoldsQuarantines:   "Quarantines in their city are not removed by outbreaks",
+	ContainsOnEntry:    "Removes 1 cube of each disease with 2 or more cubes in the cities they enter",
+	MovesOtherPawns:    "Moves other pawns as their own, or to a city with another pawn",
+	FliesWithinTwo:     "Flies to a city up to 2 connections away, taking a passenger",
+	FliesFromStation:   "Flies from a research station to any city by discarding any city card",
+	BuildsWithoutCard:  "Builds a research station without discarding a card",
+	GivesAnyCityCard:   "Gives any city card when sharing knowledge",
+	TakesCardFromOther: "Once per turn takes a card from a player in the same city",
+	StoresEventCard:    "Takes an event card from the discard pile and keeps it on their role card",
+	RecoversCityCard:   "Once per turn takes the card of their city back from the discard pile",
+	PeeksInfectionDeck: "Looks at the top infection cards at the start of their turn",
+	CollectsSamples:    "Collects cubes as samples to cure with fewer cards",
+}
+
+func (a Ability) Description() string {
+	if description, ok := abilityDescriptions[a]; ok {
+		return description
+	}
+	return string(a)
+}
+
+// Abilities are the rules a character plays by, which the rules and the
+// probability code consult instead of the character type.
+type Abilities interface {
+	ActionsPerTurn() int
+	HandLimit() int
+	CureModifier() CureModifier
+	Has(a Ability) bool
+	List() []Ability
+}
+
+type characterAbilities struct {
+	actions   int
+	handLimit int
+	cure      CureModifier
+	abilities []Ability
+}
+
+func (c characterAbilities) ActionsPerTurn() int {
+	if c.actions > 0 {
+		return c.actions
+	}
+	return ActionsPerTurn
+}
+
+func (c characterAbilities) HandLimit() int {
+	if c.handLimit > 0 {
+		return c.handLimit
+	}
+	return HandLimit
+}
+
+func (c characterAbilities) CureModifier() CureModifier {
+	return c.cure
+}
+
+func (c characterAbilities) Has(a Ability) bool {
+	for _, ability := range c.abilities {
+		if ability == a {
+			return true
+		}
+	}
+	return false
+}
+
+func (c characterAbilities) List() []Ability {
+	return c.abilities
+}
+
+var characterCatalogue = map[CharacterType]characterAbilities{
+	Medic:                 {abilities: []Ability{TreatsAllCubes, TreatsCuredOnEntry, PreventsCuredCubes}},
+	Dispatcher:            {abilities: []Ability{MovesOtherPawns}},
+	Researcher:            {abilities: []Ability{GivesAnyCityCard}},
+	Scientist:             {cure: CureModifier{Cards: -1}},
+	QuarantineSpecialist:  {abilities: []Ability{HoldsQuarantines}},
+	Colonel:               {cure: CureModifier{Cards: 2}},
+	OperationsExpert:      {abilities: []Ability{BuildsWithoutCard, FliesFromStation}},
+	Generalist:            {actions: 5},
+	Soldier:               {cure: CureModifier{CannotCure: true}},
+	Epidemiologist:        {abilities: []Ability{TakesCardFromOther}},
+	Pilot:                 {abilities: []Ability{FliesWithinTwo}},
+	FieldOperative:        {abilities: []Ability{CollectsSamples}},
+	Troubleshooter:        {abilities: []Ability{PeeksInfectionDeck}},
+	Archivist:             {handLimit: 8, abilities: []Ability{RecoversCityCard}},
+	ContainmentSpecialist: {abilities: []Ability{ContainsOnEntry}},
+	ContingencyPlanner:    {abilities: []Ability{StoresEventCard}},
+}
+
+// AbilitiesOf looks up the abilities of a character. Characters that are
+// not in the catalogue, and players without a character, play by the base
+// rules.
+func AbilitiesOf(c *Character) Abilities {
+	if c == nil {
+		return characterAbilities{}
+	}
+	return characterCatalogue[c.Type]
+}
+
+func (p *Player) Abilities() Abilities {
+	return AbilitiesOf(p.Character)
+}
+
+func (p *Player) HasAbility(a Ability) bool {
+	return p.Abilities().Has(a)
+}
+
+// playersWith lists the players whose character has the ability.
+func (gs *GameState) playersWith(a Ability) []*Player {
+	players := []*Player{}
+	for _, player := range gs.GameTurns.PlayerOrder {
+		if player.HasAbility(a) {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+// abilityPresent is true if a player with the ability is in the city.
+func (gs *GameState) abilityPresent(cn CityName, a Ability) bool {
+	for _, player := range gs.playersWith(a) {
+		if player.Location == cn {
+			return true
+		}
+	}
+	return false
+}
+
+// enterCity applies the abilities that trigger when the player enters a
+// city.
+func (gs *GameState) enterCity(player *Player, cn CityName) {
+	if player.HasAbility(TreatsCuredOnEntry) {
+		gs.medicTreat(cn)
+	}
+	if player.HasAbility(ContainsOnEntry) {
+		gs.contain(cn)
+	}
+}
+
+// contain removes 1 cube of every disease with at least 2 cubes in the city.
+func (gs *GameState) contain(cn CityName) {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return
+	}
+	for _, data := range gs.DiseaseData {
+		if before := city.CubesOf(data.Type); before >= 2 {
+			city.TreatInfections(data.Type, 1)
+			gs.placeCubes(city, data.Type, before)
+		}
+	}
+}
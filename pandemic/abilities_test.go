@@ -0,0 +1,74 @@
+package pandemic
+
+import "testing"
+
+func TestAbilitiesOf(t *testing.T) {
+	if abilities := AbilitiesOf(nil); abilities.ActionsPerTurn() != ActionsPerTurn || abilities.HandLimit() != HandLimit || len(abilities.List()) != 0 {
+		t.Fatal("Players without a character should play by the base rules")
+	}
+	if AbilitiesOf(&Character{Type: Generalist}).ActionsPerTurn() != 5 {
+		t.Fatal("The Generalist should have 5 actions")
+	}
+	if AbilitiesOf(&Character{Type: Archivist}).HandLimit() != 8 {
+		t.Fatal("The Archivist should have a hand limit of 8")
+	}
+	if !AbilitiesOf(&Character{Type: Soldier}).CureModifier().CannotCure {
+		t.Fatal("The Soldier should not be able to cure")
+	}
+	unknown := AbilitiesOf(&Character{Type: "Mystery"})
+	if unknown.ActionsPerTurn() != ActionsPerTurn || unknown.Has(HoldsQuarantines) {
+		t.Fatal("Unknown characters should play by the base rules")
+	}
+	for ct, abilities := range characterCatalogue {
+		for _, ability := range abilities.List() {
+			if _, ok := abilityDescriptions[ability]; !ok {
+				t.Fatalf("%v has the undescribed ability %v", ct, ability)
+			}
+		}
+	}
+}
+
+func TestQuarantineSpecialistHoldsQuarantines(t *testing.T) {
+	gs := newTestGame(t)
+	specialist := gs.GameTurns.PlayerOrder[0]
+	specialist.Character.Type = QuarantineSpecialist
+	if err := gs.MovePlayer(specialist, "washington"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Quarantine("washington"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfections("atlanta", "", 3); err != nil {
+		t.Fatal(err)
+	}
+	result, err := gs.Infect("atlanta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	washington, _ := gs.GetCity("washington")
+	if !washington.Quarantined || washington.TotalCubes() != 0 || len(result.QuarantinesConsumed) != 0 {
+		t.Fatal("The quarantine specialist should keep the quarantine in place")
+	}
+}
+
+func TestContainmentSpecialistOnEntry(t *testing.T) {
+	gs := newTestGame(t)
+	specialist := gs.GameTurns.PlayerOrder[0]
+	specialist.Character.Type = ContainmentSpecialist
+	if err := gs.SetInfections("paris", "", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfections("paris", Black.Type, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.MovePlayer(specialist, "paris"); err != nil {
+		t.Fatal(err)
+	}
+	paris, _ := gs.GetCity("paris")
+	if paris.CubesOf(Blue.Type) != 2 || paris.CubesOf(Black.Type) != 1 {
+		t.Fatalf("Expected one blue cube to be removed, got %v", paris.Cubes)
+	}
+	if gs.DiseaseSupply[Blue.Type] != CubesPerDisease-2 {
+		t.Fatalf("The removed cube should go back to the supply, got %v", gs.DiseaseSupply[Blue.Type])
+	}
+}
@@ -0,0 +1,311 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActionType is one of the actions a player takes on their turn.
+type ActionType string
+
+const (
+	DriveAction          ActionType = "drive"
+	DirectFlightAction   ActionType = "direct-flight"
+	CharterFlightAction  ActionType = "charter-flight"
+	ShuttleFlightAction  ActionType = "shuttle-flight"
+	BuildStationAction   ActionType = "build-station"
+	TreatAction          ActionType = "treat"
+	ShareKnowledgeAction ActionType = "share-knowledge"
+	DiscoverCureAction   ActionType = "discover-cure"
+	// Character specials.
+	DispatchAction      ActionType = "dispatch"
+	PilotFlightAction   ActionType = "pilot-flight"
+	StationFlightAction ActionType = "station-flight"
+)
+
+// Move is a legal way to move a pawn to a city, and the card the moving
+// player discards for it, if any.
+type Move struct {
+	Action ActionType
+	Card   CardName
+}
+
+// costsCard is true for moves that discard a card, or that need one to be
+// chosen.
+func (m Move) costsCard() bool {
+	return m.Card != "" || m.Action == StationFlightAction
+}
+
+func (m Move) String() string {
+	if m.Card != "" {
+		return fmt.Sprintf("%v (discarding %v)", m.Action, m.Card)
+	}
+	return string(m.Action)
+}
+
+// ActionsRemaining is the number of actions the current player can still
+// take this turn.
+func (gs *GameState) ActionsRemaining() int {
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil || curTurn.Phase != ActionsPhase {
+		return 0
+	}
+	remaining := curTurn.Player.Abilities().ActionsPerTurn() - curTurn.ActionsUsed
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// requireAction rejects actions outside of the actions phase or once the
+// current player has used all of their actions.
+func (gs *GameState) requireAction(what string) error {
+	if err := gs.requirePhase(what, ActionsPhase); err != nil {
+		return err
+	}
+	if gs.checksRules() && gs.ActionsRemaining() == 0 {
+		return fmt.Errorf("Cannot %v, there are no actions left. %v", what, gs.PhaseStatus())
+	}
+	return nil
+}
+
+// actingPlayer is the player taking the current turn's actions.
+func (gs *GameState) actingPlayer() *Player {
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		return nil
+	}
+	return curTurn.Player
+}
+
+// spend discards a card the rules ask for. When the rules are ignored a
+// card the player does not hold is skipped instead.
+func (gs *GameState) spend(player *Player, card CardName) error {
+	if card == "" || (!gs.checksRules() && !player.HasCard(card)) {
+		return nil
+	}
+	return player.Discard(card)
+}
+
+// LegalMoves lists the ways the mover can move the pawn to the city, the
+// moves that cost no card first. Only a character who moves other pawns can
+// move a pawn other than their own, using their own cards.
+func (gs *GameState) LegalMoves(mover, pawn *Player, cn CityName) []Move {
+	from, err := gs.GetCity(pawn.Location)
+	if err != nil {
+		return nil
+	}
+	to, err := gs.GetCity(cn)
+	if err != nil || from == to || !to.PanicLevel.CanMoveInto() {
+		return nil
+	}
+	own := mover == pawn
+	if !own && !mover.HasAbility(MovesOtherPawns) {
+		return nil
+	}
+	moves := []Move{}
+	if from.IsNeighbor(cn) {
+		moves = append(moves, Move{Action: DriveAction})
+	}
+	if from.ResearchStation && to.ResearchStation {
+		moves = append(moves, Move{Action: ShuttleFlightAction})
+	}
+	if !own && gs.otherPawnIn(pawn, cn) {
+		moves = append(moves, Move{Action: DispatchAction})
+	}
+	if own && mover.HasAbility(FliesWithinTwo) && gs.withinTwo(from, cn) {
+		moves = append(moves, Move{Action: PilotFlightAction})
+	}
+	if mover.HasCard(cn.CardName()) {
+		moves = append(moves, Move{Action: DirectFlightAction, Card: cn.CardName()})
+	}
+	if mover.HasCard(from.Name.CardName()) {
+		moves = append(moves, Move{Action: CharterFlightAction, Card: from.Name.CardName()})
+	}
+	if own && mover.HasAbility(FliesFromStation) && from.ResearchStation && len(mover.CityCards()) > 0 {
+		moves = append(moves, Move{Action: StationFlightAction})
+	}
+	return moves
+}
+
+// otherPawnIn is true if a pawn other than the given one is in the city.
+func (gs *GameState) otherPawnIn(pawn *Player, cn CityName) bool {
+	for _, player := range gs.GameTurns.PlayerOrder {
+		if player != pawn && player.Location == cn {
+			return true
+		}
+	}
+	return false
+}
+
+// withinTwo is true if the city is at most 2 connections away.
+func (gs *GameState) withinTwo(from *City, cn CityName) bool {
+	distance := gs.Board().Distance(from.Name, cn)
+	return distance >= 0 && distance <= 2
+}
+
+// MovePawn moves a pawn as an action of the current player. Without an
+// action the move that costs no card is taken, or the only legal move if
+// every one of them costs a card. A station flight discards the given card.
+// While the rules are ignored the pawn is moved whether or not the move is
+// legal.
+func (gs *GameState) MovePawn(pawn *Player, cn CityName, action ActionType, card CardName) error {
+	if err := gs.requireAction("move"); err != nil {
+		return err
+	}
+	mover := gs.actingPlayer()
+	if mover == nil {
+		mover = pawn
+	}
+	move, err := gs.chooseMove(mover, pawn, cn, action)
+	if err != nil {
+		return err
+	}
+	if move.Action == StationFlightAction {
+		if gs.checksRules() && !mover.HasCityCard(card) {
+			return fmt.Errorf("%v has to discard a city card they hold to fly from a research station", mover.HumanName)
+		}
+		move.Card = card
+	}
+	if err := gs.placePlayer(pawn, cn); err != nil {
+		return err
+	}
+	if err := gs.spend(mover, move.Card); err != nil {
+		return err
+	}
+	gs.useAction()
+	gs.record(Event{Type: MoveEvent, Player: pawn.HumanName, City: cn, Action: move.Action, Card: move.Card})
+	return nil
+}
+
+func (gs *GameState) chooseMove(mover, pawn *Player, cn CityName, action ActionType) (Move, error) {
+	moves := gs.LegalMoves(mover, pawn, cn)
+	if action != "" {
+		for _, move := range moves {
+			if move.Action == action {
+				return move, nil
+			}
+		}
+		if !gs.checksRules() {
+			return Move{Action: action}, nil
+		}
+		return Move{}, fmt.Errorf("%v cannot %v to %v. %v", pawn.HumanName, action, cn, describeMoves(moves))
+	}
+	if !gs.checksRules() {
+		return Move{}, nil
+	}
+	if len(moves) == 0 {
+		return Move{}, fmt.Errorf("%v cannot move to %v. %v", pawn.HumanName, cn, describeMoves(moves))
+	}
+	if !moves[0].costsCard() || len(moves) == 1 {
+		return moves[0], nil
+	}
+	return Move{}, fmt.Errorf("Choose how %v moves to %v. %v", pawn.HumanName, cn, describeMoves(moves))
+}
+
+func describeMoves(moves []Move) string {
+	if len(moves) == 0 {
+		return "There is no legal move there"
+	}
+	names := []string{}
+	for _, move := range moves {
+		names = append(names, move.String())
+	}
+	return fmt.Sprintf("Legal moves: %v", strings.Join(names, ", "))
+}
+
+// BuildStation builds a research station in the player's city, discarding
+// the city's card unless the character builds without one.
+func (gs *GameState) BuildStation(player *Player) error {
+	if err := gs.requireAction("build a research station"); err != nil {
+		return err
+	}
+	city, err := gs.GetCity(player.Location)
+	if err != nil {
+		return err
+	}
+	if err := gs.checkNewStation(city); err != nil {
+		return err
+	}
+	var card CardName
+	if !player.HasAbility(BuildsWithoutCard) {
+		card = city.Name.CardName()
+	}
+	if gs.checksRules() && card != "" && !player.HasCard(card) {
+		return fmt.Errorf("%v needs the %v card to build a research station there", player.HumanName, card)
+	}
+	if err := gs.spend(player, card); err != nil {
+		return err
+	}
+	city.ResearchStation = true
+	gs.useAction()
+	gs.record(Event{Type: BuildStationEvent, Player: player.HumanName, City: city.Name})
+	return nil
+}
+
+// Treat removes a cube of the disease from the player's city, or every cube
+// of it if the disease is cured or the character treats all cubes. An empty
+// disease means the city's own disease.
+func (gs *GameState) Treat(player *Player, dt DiseaseType) error {
+	if err := gs.requireAction("treat diseases"); err != nil {
+		return err
+	}
+	city, err := gs.GetCity(player.Location)
+	if err != nil {
+		return err
+	}
+	if dt == "" {
+		dt = city.Disease
+	}
+	if gs.DataForDisease(dt).Untreatable {
+		return fmt.Errorf("%v cannot be treated", dt)
+	}
+	before := city.CubesOf(dt)
+	if before == 0 {
+		return fmt.Errorf("%v has no %v cubes to treat", city.Name, dt)
+	}
+	treated := 1
+	if gs.IsCured(dt) || player.HasAbility(TreatsAllCubes) {
+		treated = before
+	}
+	city.TreatInfections(dt, treated)
+	gs.placeCubes(city, dt, before)
+	gs.checkEradication(dt)
+	gs.useAction()
+	gs.record(Event{Type: TreatCubesEvent, Player: player.HumanName, City: city.Name, Disease: dt})
+	return nil
+}
+
+// checkShare checks that a card can be shared between the players: they are
+// in the same city, one of them is taking the turn, and the card is the card
+// of that city, or any city card for a character who gives any city card.
+func (gs *GameState) checkShare(from, to *Player, name CardName) error {
+	if from.Location != to.Location {
+		return fmt.Errorf("%v and %v have to be in the same city to share knowledge", from.HumanName, to.HumanName)
+	}
+	if acting := gs.actingPlayer(); acting != from && acting != to {
+		return fmt.Errorf("Only %v can share knowledge on their turn", acting.HumanName)
+	}
+	if name == from.Location.CardName() {
+		return nil
+	}
+	if from.HasAbility(GivesAnyCityCard) && from.HasCityCard(name) {
+		return nil
+	}
+	return fmt.Errorf("Only the %v card can be shared in %v", from.Location, from.Location)
+}
+
+// requireStation rejects curing away from a research station.
+func (gs *GameState) requireStation(player *Player) error {
+	if !gs.checksRules() {
+		return nil
+	}
+	city, err := gs.GetCity(player.Location)
+	if err != nil {
+		return err
+	}
+	if !city.ResearchStation {
+		return fmt.Errorf("%v has to be at a research station to discover a cure", player.HumanName)
+	}
+	return nil
+}
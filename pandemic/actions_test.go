@@ -0,0 +1,177 @@
+package pandemic
+
+import "testing"
+
+// startWithCards deals the cards to the first player and starts the game.
+func startWithCards(t *testing.T, gs *GameState, cards ...CardName) *Player {
+	for _, card := range cards {
+		if err := gs.StartDrawCard(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gs.StartGame()
+	return gs.GameTurns.PlayerOrder[0]
+}
+
+func TestMoveActions(t *testing.T) {
+	gs := newTestGame(t)
+	player := startWithCards(t, gs, "paris", "atlanta")
+
+	if err := gs.MovePlayer(player, "chicago"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.MovePawn(player, "paris", DriveAction, ""); err == nil {
+		t.Fatal("Paris is not a neighbor of Chicago")
+	}
+	if err := gs.MovePlayer(player, "atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if len(player.Cards) != 2 || gs.ActionsRemaining() != 2 {
+		t.Fatalf("Driving should only cost actions, got %v cards and %v actions left", len(player.Cards), gs.ActionsRemaining())
+	}
+	if err := gs.MovePlayer(player, "paris"); err == nil {
+		t.Fatal("Choosing between a direct and a charter flight should be left to the player")
+	}
+	if err := gs.MovePawn(player, "paris", CharterFlightAction, ""); err != nil {
+		t.Fatal(err)
+	}
+	if player.HasCard("atlanta") || !player.HasCard("paris") {
+		t.Fatal("A charter flight should discard the card of the city left")
+	}
+	if err := gs.MovePawn(player, "tokyo", ShuttleFlightAction, ""); err == nil {
+		t.Fatal("Shuttle flights need research stations")
+	}
+	if err := gs.MovePlayer(player, "london"); err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := gs.GameTurns.CurrentTurn(); cur.Phase != DrawPhase || gs.ActionsRemaining() != 0 {
+		t.Fatalf("The fourth action should end the actions, got the %v phase", cur.Phase)
+	}
+	if err := gs.MovePlayer(player, "paris"); err == nil {
+		t.Fatal("Moving should be rejected without actions left")
+	}
+
+	rebuilt, err := gs.Journal.rebuild("test", gs.Journal.Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marshalState(t, rebuilt) != marshalState(t, gs) {
+		t.Fatal("Moves should replay with the cards they discarded")
+	}
+}
+
+func TestCorrectionsTakeNoAction(t *testing.T) {
+	gs := newTestGame(t)
+	player := startWithCards(t, gs, "paris")
+
+	gs.IgnoreRules = true
+	for _, cn := range []CityName{"tokyo", "paris", "london", "madrid"} {
+		if err := gs.MovePlayer(player, cn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gs.IgnoreRules = false
+	if cur, _ := gs.GameTurns.CurrentTurn(); cur.Phase != ActionsPhase || gs.ActionsRemaining() != 4 {
+		t.Fatalf("Corrections should take no action, got the %v phase and %v actions left", cur.Phase, gs.ActionsRemaining())
+	}
+	if err := gs.MovePlayer(player, "paris"); err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt, err := gs.Journal.rebuild("test", gs.Journal.Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt.ActionsRemaining() != 3 || marshalState(t, rebuilt) != marshalState(t, gs) {
+		t.Fatalf("Only the move after the corrections should take an action when replayed, got %v left", rebuilt.ActionsRemaining())
+	}
+}
+
+func TestBuildTreatAndCure(t *testing.T) {
+	gs := newTestGame(t)
+	if err := gs.RemoveStation("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfections("atlanta", "", 2); err != nil {
+		t.Fatal(err)
+	}
+	player := startWithCards(t, gs, "atlanta", "chicago", "washington", "newyork", "montreal", "london")
+	if err := gs.Treat(player, ""); err != nil {
+		t.Fatal(err)
+	}
+	atlanta, _ := gs.GetCity("atlanta")
+	if atlanta.CubesOf(Blue.Type) != 1 {
+		t.Fatalf("Treating should remove 1 cube, got %v left", atlanta.CubesOf(Blue.Type))
+	}
+	if err := gs.Cure(player, Blue.Type, nil); err == nil {
+		t.Fatal("Curing should need a research station")
+	}
+	if err := gs.BuildStation(player); err != nil {
+		t.Fatal(err)
+	}
+	if !atlanta.ResearchStation || player.HasCard("atlanta") {
+		t.Fatal("Building should place a station and discard the city's card")
+	}
+	if err := gs.BuildStation(player); err == nil {
+		t.Fatal("A city only has one research station")
+	}
+	if err := gs.Cure(player, Blue.Type, nil); err != nil {
+		t.Fatal(err)
+	}
+	atlanta.SetInfections(Blue.Type, 3)
+	if err := gs.Treat(player, Blue.Type); err != nil {
+		t.Fatal(err)
+	}
+	if atlanta.CubesOf(Blue.Type) != 0 {
+		t.Fatal("Treating a cured disease should remove every cube")
+	}
+}
+
+func TestShareKnowledge(t *testing.T) {
+	gs := newTestGame(t)
+	other := gs.GameTurns.PlayerOrder[1]
+	player := startWithCards(t, gs, "paris", "atlanta")
+	if err := gs.ExchangeCard(player, other, "paris"); err == nil {
+		t.Fatal("Only the card of the city they are in can be shared")
+	}
+	if err := gs.ExchangeCard(player, other, "atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetLocation(other, "chicago"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.ExchangeCard(other, player, "atlanta"); err == nil {
+		t.Fatal("Players in different cities cannot share knowledge")
+	}
+
+	player.Character.Type = Researcher
+	if err := gs.MovePlayer(player, "chicago"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.ExchangeCard(player, other, "paris"); err != nil {
+		t.Fatalf("The Researcher should give any city card: %v", err)
+	}
+}
+
+func TestMovementSpecials(t *testing.T) {
+	gs := newTestGame(t)
+	other := gs.GameTurns.PlayerOrder[1]
+	if err := gs.SetLocation(other, "tokyo"); err != nil {
+		t.Fatal(err)
+	}
+	player := startWithCards(t, gs)
+	if err := gs.MovePlayer(other, "seoul"); err == nil {
+		t.Fatal("Only the Dispatcher moves other pawns")
+	}
+	player.Character.Type = Dispatcher
+	if err := gs.MovePlayer(other, "atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.MovePlayer(player, "montreal"); err == nil {
+		t.Fatal("Montreal is 2 connections away from Atlanta")
+	}
+	player.Character.Type = Pilot
+	if err := gs.MovePlayer(player, "montreal"); err != nil {
+		t.Fatalf("The Pilot should fly 2 connections away: %v", err)
+	}
+}
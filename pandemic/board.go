@@ -0,0 +1,186 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Board is the graph of the cities, with every neighbor resolved to its
+// city. It is built once when a game is created or loaded, so that looking
+// up cities and following their neighbors does not search the whole list.
+// Neighbors that are not cities of the board are left out.
+type Board struct {
+	names     []CityName
+	index     map[CityName]int
+	cities    []*City
+	adjacency [][]int
+	neighbors [][]*City
+}
+
+func NewBoard(cities Cities) *Board {
+	board := &Board{
+		names:     make([]CityName, len(cities)),
+		index:     map[CityName]int{},
+		cities:    make([]*City, len(cities)),
+		adjacency: make([][]int, len(cities)),
+		neighbors: make([][]*City, len(cities)),
+	}
+	for i, city := range cities {
+		board.names[i] = city.Name
+		board.index[city.Name] = i
+		board.cities[i] = city
+	}
+	for i, city := range cities {
+		for _, neighbor := range city.Neighbors {
+			if j, ok := board.index[CityName(neighbor)]; ok {
+				board.adjacency[i] = append(board.adjacency[i], j)
+				board.neighbors[i] = append(board.neighbors[i], cities[j])
+			}
+		}
+	}
+	return board
+}
+
+// Board returns the game's board, building it for games that were not
+// created or loaded through NewGame or LoadGame.
+func (gs *GameState) Board() *Board {
+	if gs.board == nil {
+		gs.board = NewBoard(*gs.Cities)
+	}
+	return gs.board
+}
+
+func (b *Board) City(cn CityName) (*City, error) {
+	if i, ok := b.index[cn]; ok {
+		return b.cities[i], nil
+	}
+	return nil, fmt.Errorf("No city named %v", cn)
+}
+
+// CityByPrefix finds the city named exactly as the prefix, or else the only
+// city whose name starts with it.
+func (b *Board) CityByPrefix(prefix string) (*City, error) {
+	if city, err := b.City(CityName(prefix)); err == nil {
+		return city, nil
+	}
+	var ret *City
+	prefix = strings.ToLower(prefix)
+	for i, cn := range b.names {
+		if strings.HasPrefix(strings.ToLower(string(cn)), prefix) {
+			if ret != nil {
+				return nil, fmt.Errorf("'%v' is ambiguous", prefix)
+			}
+			ret = b.cities[i]
+		}
+	}
+	if ret == nil {
+		return nil, fmt.Errorf("%v is not a prefix for any city", prefix)
+	}
+	return ret, nil
+}
+
+// NeighborCities lists the cities connected to the city.
+func (b *Board) NeighborCities(cn CityName) []*City {
+	if i, ok := b.index[cn]; ok {
+		return b.neighbors[i]
+	}
+	return nil
+}
+
+// Cities lists the cities of the board in the order of the distance matrix.
+func (b *Board) Cities() []CityName {
+	return b.names
+}
+
+func (b *Board) Neighbors(cn CityName) []CityName {
+	i, ok := b.index[cn]
+	if !ok {
+		return nil
+	}
+	neighbors := []CityName{}
+	for _, j := range b.adjacency[i] {
+		neighbors = append(neighbors, b.names[j])
+	}
+	return neighbors
+}
+
+// bfs returns the number of connections from the city to every city of the
+// board, -1 for the ones it cannot reach, and the city each one is reached
+// from.
+func (b *Board) bfs(from int) ([]int, []int) {
+	distances := make([]int, len(b.names))
+	parents := make([]int, len(b.names))
+	for i := range distances {
+		distances[i] = -1
+		parents[i] = -1
+	}
+	distances[from] = 0
+	queue := []int{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range b.adjacency[cur] {
+			if distances[next] < 0 {
+				distances[next] = distances[cur] + 1
+				parents[next] = cur
+				queue = append(queue, next)
+			}
+		}
+	}
+	return distances, parents
+}
+
+// Distances is the number of connections from the city to every city it
+// can reach.
+func (b *Board) Distances(from CityName) map[CityName]int {
+	i, ok := b.index[from]
+	if !ok {
+		return nil
+	}
+	distances, _ := b.bfs(i)
+	ret := map[CityName]int{}
+	for j, distance := range distances {
+		if distance >= 0 {
+			ret[b.names[j]] = distance
+		}
+	}
+	return ret
+}
+
+// Distance is the number of connections between the cities, or -1 if
+// there is no path between them.
+func (b *Board) Distance(from, to CityName) int {
+	if distance, ok := b.Distances(from)[to]; ok {
+		return distance
+	}
+	return -1
+}
+
+// ShortestPath lists the cities driven through from one city to the other,
+// both included, or nil if there is no path between them.
+func (b *Board) ShortestPath(from, to CityName) []CityName {
+	i, ok := b.index[from]
+	j, ok2 := b.index[to]
+	if !ok || !ok2 {
+		return nil
+	}
+	distances, parents := b.bfs(i)
+	if distances[j] < 0 {
+		return nil
+	}
+	path := make([]CityName, distances[j]+1)
+	for cur, k := j, distances[j]; cur >= 0; cur, k = parents[cur], k-1 {
+		path[k] = b.names[cur]
+	}
+	return path
+}
+
+// DistanceMatrix holds the number of connections between every pair of
+// cities, indexed in the order of Cities.
+func (b *Board) DistanceMatrix() [][]int {
+	matrix := make([][]int, len(b.names))
+	for i := range b.names {
+		matrix[i], _ = b.bfs(i)
+	}
+	return matrix
+}
@@ -0,0 +1,159 @@
+package pandemic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBoardDistances(t *testing.T) {
+	board := newTestGame(t).Board()
+	if !reflect.DeepEqual(board.Neighbors("atlanta"), []CityName{"chicago", "washington", "miami"}) {
+		t.Fatalf("Unexpected neighbors of atlanta: %v", board.Neighbors("atlanta"))
+	}
+	if board.Distance("atlanta", "atlanta") != 0 || board.Distance("atlanta", "montreal") != 2 {
+		t.Fatal("Montreal should be 2 connections from Atlanta")
+	}
+	path := board.ShortestPath("atlanta", "tokyo")
+	if len(path) != board.Distance("atlanta", "tokyo")+1 || path[0] != "atlanta" || path[len(path)-1] != "tokyo" {
+		t.Fatalf("Unexpected path from atlanta to tokyo: %v", path)
+	}
+	for i := 1; i < len(path); i++ {
+		if board.Distance(path[i-1], path[i]) != 1 {
+			t.Fatalf("%v and %v are not neighbors", path[i-1], path[i])
+		}
+	}
+	if board.Distance("atlanta", "nowhere") != -1 || board.ShortestPath("atlanta", "nowhere") != nil {
+		t.Fatal("Unknown cities cannot be reached")
+	}
+
+	matrix := board.DistanceMatrix()
+	for i := range matrix {
+		for j := range matrix {
+			if matrix[i][j] != matrix[j][i] || (i == j) != (matrix[i][j] == 0) {
+				t.Fatalf("The distance matrix should be symmetric, got %v and %v", matrix[i][j], matrix[j][i])
+			}
+		}
+	}
+}
+
+func TestPlanRoute(t *testing.T) {
+	gs := newTestGame(t)
+	player := gs.GameTurns.PlayerOrder[0]
+
+	route, err := gs.PlanRoute(player, "atlanta", "paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Actions() != gs.Board().Distance("atlanta", "paris") || len(route.Cards()) != 0 {
+		t.Fatalf("Without cards the route should drive, got %v", route)
+	}
+
+	player.Cards = []*CityCard{{CityName: "atlanta"}, {CityName: "paris"}}
+	if route, _ = gs.PlanRoute(player, "atlanta", "paris"); route.Actions() != 1 {
+		t.Fatalf("Expected a flight to paris, got %v", route)
+	}
+	if route, _ = gs.PlanRoute(player, "chicago", "cairo"); route.Actions() != 2 || !reflect.DeepEqual(route.Cards(), []CardName{"atlanta"}) {
+		t.Fatalf("Expected to drive to atlanta and charter a flight, got %v", route)
+	}
+	player.Cards = []*CityCard{{CityName: "paris"}}
+	if route, _ = gs.PlanRoute(player, "atlanta", "cairo"); route.Actions() != 1+gs.Board().Distance("paris", "cairo") || len(route.Cards()) != 1 {
+		t.Fatalf("The paris card should only be spent once, got %v", route)
+	}
+
+	player.Cards = nil
+	if err := gs.PlaceStation("paris"); err != nil {
+		t.Fatal(err)
+	}
+	if route, _ = gs.PlanRoute(player, "atlanta", "madrid"); route.Actions() != 2 || route.Steps[0].Action != ShuttleFlightAction {
+		t.Fatalf("Expected a shuttle flight to paris, got %v", route)
+	}
+}
+
+func TestPlanRouteFliesFromStationOnce(t *testing.T) {
+	gs := newTestGame(t)
+	player := gs.GameTurns.PlayerOrder[0]
+	player.Character.Type = OperationsExpert
+	player.Cards = []*CityCard{{CityName: "lima"}, {CityName: "tokyo"}, {CityName: "cairo"}}
+	for _, cn := range []CityName{"atlanta", "paris", "bangkok"} {
+		if city, _ := gs.GetCity(cn); !city.ResearchStation {
+			if err := gs.PlaceStation(cn); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	route, err := gs.PlanRoute(player, "chicago", "sydney")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Actions() != 2 || route.Steps[1].Action != StationFlightAction {
+		t.Fatalf("Expected to drive to atlanta and fly from its station, got %v", route)
+	}
+	for _, cn := range gs.Cities.CityNames() {
+		route, err := gs.PlanRoute(player, "chicago", cn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		flights := 0
+		for _, step := range route.Steps {
+			if step.Action == StationFlightAction {
+				flights++
+			}
+		}
+		if flights > 1 {
+			t.Fatalf("The Operations Expert flies from a station once per turn, got %v", route)
+		}
+	}
+}
+
+// benchmarkGame is a game with cities on the verge of outbreaking, so that
+// the probability code follows their neighbors.
+func benchmarkGame(b *testing.B) *GameState {
+	gs := newTestGame(b)
+	for _, cn := range []CityName{"atlanta", "chicago", "washington", "miami", "paris", "essen"} {
+		if err := gs.SetInfections(cn, Blue.Type, 3); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return gs
+}
+
+// BenchmarkRenderCities does the city lookups of one render of the city
+// view and the chain risk panel.
+func BenchmarkRenderCities(b *testing.B) {
+	gs := benchmarkGame(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, cn := range gs.SortBySeverity(gs.Cities.CityNames()) {
+			if _, err := gs.GetCity(cn); err != nil {
+				b.Fatal(err)
+			}
+			gs.ProbabilityOfCity(cn)
+			gs.CanOutbreak(cn)
+		}
+		gs.ChainClusters()
+	}
+}
+
+func BenchmarkOutbreakCascade(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		gs := benchmarkGame(b)
+		b.StartTimer()
+		if _, err := gs.Infect("atlanta"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetCityByPrefix(b *testing.B) {
+	gs := benchmarkGame(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, cn := range gs.Cities.CityNames() {
+			if _, err := GetCityByPrefix(string(cn), gs); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
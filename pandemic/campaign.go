@@ -0,0 +1,243 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// campaignMonths is the order of months in a Legacy campaign. Each month
+// can be played a second time, named with a '2' suffix, if the first
+// attempt was lost.
+var campaignMonths = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+// MonthResult is the outcome of one game of a Legacy campaign, along with
+// everything about the board that carries over into later months.
+type MonthResult struct {
+	Month          string                     `json:"month"`
+	Won            bool                       `json:"won"`
+	FundingLevel   int                        `json:"funding_level"`
+	Outbreaks      int                        `json:"outbreaks"`
+	PanicLevels    map[CityName]PanicLevel    `json:"panic_levels"`
+	FadedCities    []CityName                 `json:"faded_cities"`
+	CharactersLost []CharacterType            `json:"characters_lost"`
+	Scars          map[CharacterType][]string `json:"scars"`
+}
+
+// Campaign tracks a Legacy campaign across months. Setup holds the new game
+// settings of the first month; the settings of every later month are
+// derived from it and the recorded results. FundingLevel is the level of
+// the month being played, and FundedEvents the events chosen for it, if
+// they were chosen with the setup command.
+type Campaign struct {
+	Setup        json.RawMessage `json:"setup"`
+	Months       []MonthResult   `json:"months"`
+	Current      MonthResult     `json:"current"`
+	FundingLevel int             `json:"funding_level"`
+	FundedEvents []*FundedEvent  `json:"funded_events,omitempty"`
+}
+
+// Funding moves down after a won month and up after a lost one.
+const fundingChange = 2
+
+func NewCampaign(newGameFile string) (*Campaign, error) {
+	data, err := ioutil.ReadFile(newGameFile)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read new game file at %v: %v", newGameFile, err)
+	}
+	var settings NewGameSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("Invalid new game JSON file at %v: %v", newGameFile, err)
+	}
+	fundingLevel := len(settings.FundedEvents)
+	if settings.FundingLevel != nil {
+		fundingLevel = *settings.FundingLevel
+	}
+	return &Campaign{
+		Setup:        json.RawMessage(data),
+		Months:       []MonthResult{},
+		Current:      newMonthResult(),
+		FundingLevel: fundingLevel,
+	}, nil
+}
+
+func newMonthResult() MonthResult {
+	return MonthResult{
+		PanicLevels:    map[CityName]PanicLevel{},
+		FadedCities:    []CityName{},
+		CharactersLost: []CharacterType{},
+		Scars:          map[CharacterType][]string{},
+	}
+}
+
+func LoadCampaign(campaignFile string) (*Campaign, error) {
+	data, err := ioutil.ReadFile(campaignFile)
+	if err != nil {
+		return nil, err
+	}
+	var campaign Campaign
+	if err := json.Unmarshal(data, &campaign); err != nil {
+		return nil, fmt.Errorf("Invalid campaign file %v: %v", campaignFile, err)
+	}
+	return &campaign, nil
+}
+
+func (c *Campaign) Save(campaignFile string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(campaignFile, data)
+}
+
+// NextMonth names the month that should be played next: the second attempt
+// at a lost month, or else the following month.
+func (c *Campaign) NextMonth() (string, error) {
+	if len(c.Months) == 0 {
+		return campaignMonths[0], nil
+	}
+	last := c.Months[len(c.Months)-1]
+	month := strings.TrimSuffix(last.Month, "2")
+	if !last.Won && month == last.Month {
+		return month + "2", nil
+	}
+	for i, name := range campaignMonths {
+		if name == month && i+1 < len(campaignMonths) {
+			return campaignMonths[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("The campaign is over after %v", last.Month)
+}
+
+// ChooseFundedEvents picks the funded events of the next month. There must
+// be exactly as many as the campaign's funding level.
+func (c *Campaign) ChooseFundedEvents(names []FundedEventName) error {
+	events := []*FundedEvent{}
+	for _, name := range names {
+		events = append(events, NamedFundedEvent(name))
+	}
+	fundingLevel := c.FundingLevel
+	settings := NewGameSettings{FundedEvents: events, FundingLevel: &fundingLevel}
+	if err := settings.ValidateFunding(); err != nil {
+		return err
+	}
+	c.FundedEvents = events
+	return nil
+}
+
+// LoseCharacter marks a character as lost in the month being played.
+func (c *Campaign) LoseCharacter(ct CharacterType) {
+	for _, lost := range c.Current.CharactersLost {
+		if lost == ct {
+			return
+		}
+	}
+	c.Current.CharactersLost = append(c.Current.CharactersLost, ct)
+}
+
+// Scar records a permanent scar on a character in the month being played.
+func (c *Campaign) Scar(ct CharacterType, scar string) {
+	if c.Current.Scars == nil {
+		c.Current.Scars = map[CharacterType][]string{}
+	}
+	c.Current.Scars[ct] = append(c.Current.Scars[ct], scar)
+}
+
+// EndMonth records the result of a finished game, taking the outbreaks,
+// panic levels and faded cities from its final state.
+func (c *Campaign) EndMonth(gs *GameState, won bool) MonthResult {
+	result := c.Current
+	if result.PanicLevels == nil {
+		result = newMonthResult()
+	}
+	result.Month = gs.GameName
+	result.Won = won
+	result.Outbreaks = gs.Outbreaks
+	result.FundingLevel = c.FundingLevel
+	faded := gs.DiseaseData.Faded()
+	for _, city := range *gs.Cities {
+		if city.PanicLevel != Nothing {
+			result.PanicLevels[city.Name] = city.PanicLevel
+		}
+		if faded != "" && city.Disease == faded && city.OriginalDisease != faded {
+			result.FadedCities = append(result.FadedCities, city.Name)
+		}
+	}
+	sort.Slice(result.FadedCities, func(i, j int) bool { return result.FadedCities[i] < result.FadedCities[j] })
+	c.Months = append(c.Months, result)
+	c.Current = newMonthResult()
+	if won {
+		c.FundingLevel -= fundingChange
+	} else {
+		c.FundingLevel += fundingChange
+	}
+	if c.FundingLevel < 0 {
+		c.FundingLevel = 0
+	}
+	if c.FundingLevel > MaxFundingLevel {
+		c.FundingLevel = MaxFundingLevel
+	}
+	c.FundedEvents = nil
+	return result
+}
+
+// NextSettings derives the new game settings of the next month from the
+// campaign's first month and the results recorded since. Panic levels and
+// faded cities are taken from the latest result, lost characters are
+// removed from their players and scars are accumulated. After the first
+// month start cards are cleared, since hands are dealt again every month.
+// The funding level always comes from the campaign, so the funded events
+// have to be chosen again whenever it changes.
+func (c *Campaign) NextSettings() (*NewGameSettings, error) {
+	var settings NewGameSettings
+	if err := json.Unmarshal(c.Setup, &settings); err != nil {
+		return nil, fmt.Errorf("Invalid campaign setup: %v", err)
+	}
+	fundingLevel := c.FundingLevel
+	settings.FundingLevel = &fundingLevel
+	if c.FundedEvents != nil {
+		settings.FundedEvents = c.FundedEvents
+	}
+	if len(c.Months) == 0 {
+		return &settings, nil
+	}
+	for _, player := range settings.Players {
+		player.StartCards = []CardName{}
+	}
+
+	last := c.Months[len(c.Months)-1]
+	faded := Set{}
+	for _, name := range last.FadedCities {
+		faded.Add(name)
+	}
+	diseases := settings.Diseases
+	if len(diseases) == 0 {
+		diseases = DefaultDiseases()
+	}
+	if faded.Size() > 0 && diseases.Faded() == "" {
+		return nil, fmt.Errorf("Cities have faded, but no disease in the campaign makes cities fade")
+	}
+	for _, city := range settings.Cities {
+		city.PanicLevel = last.PanicLevels[city.Name]
+		if faded.Contains(city.Name) {
+			city.Disease = diseases.Faded()
+		}
+	}
+
+	for _, month := range c.Months {
+		for _, player := range settings.Players {
+			if player.Character == nil {
+				continue
+			}
+			player.Character.Scars = append(player.Character.Scars, month.Scars[player.Character.Type]...)
+			for _, lost := range month.CharactersLost {
+				if player.Character != nil && player.Character.Type == lost {
+					player.Character = nil
+				}
+			}
+		}
+	}
+	return &settings, nil
+}
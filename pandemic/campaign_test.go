@@ -0,0 +1,157 @@
+package pandemic
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestCampaign(t *testing.T) *Campaign {
+	campaign, err := NewCampaign("../data/pandemicboard.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return campaign
+}
+
+func TestCampaignNextMonth(t *testing.T) {
+	campaign := newTestCampaign(t)
+	results := []struct {
+		month string
+		won   bool
+		next  string
+	}{
+		{"jan", true, "feb"},
+		{"feb", false, "feb2"},
+		{"feb2", false, "mar"},
+		{"mar", true, "apr"},
+	}
+	if next, _ := campaign.NextMonth(); next != "jan" {
+		t.Fatalf("A new campaign should start in jan, got %v", next)
+	}
+	for _, result := range results {
+		campaign.Months = append(campaign.Months, MonthResult{Month: result.month, Won: result.won})
+		next, err := campaign.NextMonth()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next != result.next {
+			t.Fatalf("Expected %v after %v, got %v", result.next, result.month, next)
+		}
+	}
+	campaign.Months = append(campaign.Months, MonthResult{Month: "dec", Won: true})
+	if _, err := campaign.NextMonth(); err == nil {
+		t.Fatal("Expected the campaign to be over after dec")
+	}
+}
+
+func TestCampaignCarriesBoardIntoNextMonth(t *testing.T) {
+	campaign := newTestCampaign(t)
+	gs := newTestGame(t)
+	gs.GameName = "jan"
+	paris, _ := gs.GetCity("paris")
+	paris.PanicLevel = Rioting2
+	lima, _ := gs.GetCity("lima")
+	lima.Disease = Faded.Type
+	medic := gs.GameTurns.PlayerOrder[1].Character.Type
+	planner := gs.GameTurns.PlayerOrder[0].Character.Type
+	campaign.LoseCharacter(medic)
+	campaign.Scar(planner, "Paranoid")
+
+	result := campaign.EndMonth(gs, false)
+	if result.Month != "jan" || result.Won || result.PanicLevels["paris"] != Rioting2 {
+		t.Fatalf("Unexpected month result %+v", result)
+	}
+	if len(result.FadedCities) != 1 || result.FadedCities[0] != "lima" {
+		t.Fatalf("Expected lima to have faded, got %v", result.FadedCities)
+	}
+
+	settings, err := campaign.NextSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, city := range settings.Cities {
+		switch city.Name {
+		case "paris":
+			if city.PanicLevel != Rioting2 {
+				t.Fatalf("Expected paris to still be rioting, got %v", city.PanicLevel)
+			}
+		case "lima":
+			if city.Disease != Faded.Type {
+				t.Fatalf("Expected lima to still be faded, got %v", city.Disease)
+			}
+		}
+	}
+	if settings.Players[1].Character != nil {
+		t.Fatal("The lost medic should not be played next month")
+	}
+	if scars := settings.Players[0].Character.Scars; len(scars) != 1 || scars[0] != "Paranoid" {
+		t.Fatalf("Expected the planner to keep their scar, got %v", scars)
+	}
+}
+
+func TestCampaignSaveAndLoad(t *testing.T) {
+	campaign := newTestCampaign(t)
+	gs := newTestGame(t)
+	campaign.Scar(gs.GameTurns.PlayerOrder[0].Character.Type, "Paranoid")
+	campaign.EndMonth(gs, true)
+
+	filename := filepath.Join(t.TempDir(), "campaign.json")
+	if err := campaign.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCampaign(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Months) != 1 || !loaded.Months[0].Won {
+		t.Fatalf("Expected one won month, got %+v", loaded.Months)
+	}
+	if _, err := loaded.NextSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.ChooseFundedEvents([]FundedEventName{"airlift", "forecast", "governmentgrant", "onequietnight", "remotetreatment", "infectionzoneban"}); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := loaded.NextSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewGameFromSettings(settings, "feb"); err != nil {
+		t.Fatalf("Next month's settings should start a game: %v", err)
+	}
+}
+
+func TestCampaignFundingLevel(t *testing.T) {
+	campaign := newTestCampaign(t)
+	if campaign.FundingLevel != 8 {
+		t.Fatalf("Expected the initial funding level to match the 8 funded events, got %v", campaign.FundingLevel)
+	}
+	gs := newTestGame(t)
+	campaign.EndMonth(gs, false)
+	if campaign.FundingLevel != MaxFundingLevel {
+		t.Fatalf("Expected funding to rise to %v after a loss, got %v", MaxFundingLevel, campaign.FundingLevel)
+	}
+	campaign.EndMonth(gs, true)
+	if campaign.FundingLevel != 8 || campaign.Months[1].FundingLevel != MaxFundingLevel {
+		t.Fatalf("Expected funding to drop to 8 after a win, got %v", campaign.FundingLevel)
+	}
+	settings, err := campaign.NextSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewGameFromSettings(settings, "feb2"); err != nil {
+		t.Fatalf("The base funded events should match funding level 8: %v", err)
+	}
+
+	campaign.EndMonth(gs, true)
+	settings, err = campaign.NextSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewGameFromSettings(settings, "mar"); err == nil {
+		t.Fatal("Expected 8 funded events to be rejected at funding level 6")
+	}
+	if err := campaign.ChooseFundedEvents([]FundedEventName{"airlift", "airlift"}); err == nil {
+		t.Fatal("Expected an error choosing the wrong number of funded events")
+	}
+}
@@ -0,0 +1,200 @@
+package pandemic
+
+import (
+	"sort"
+)
+
+// Cascade is what an outbreak in a city would spread to on the board as it
+// is: the cities that would outbreak in turn, and the cubes placed on the
+// neighbors that would not.
+type Cascade struct {
+	Origin  CityName
+	Disease DiseaseType
+	Chained []CityName
+	Cubes   int
+}
+
+func (c Cascade) Outbreaks() int {
+	return 1 + len(c.Chained)
+}
+
+// PredictCascade follows an outbreak of the disease in the city the way
+// HandleOutbreak would, without changing the game.
+func (gs *GameState) PredictCascade(cn CityName, dt DiseaseType) (Cascade, error) {
+	origin, err := gs.GetCity(cn)
+	if err != nil {
+		return Cascade{}, err
+	}
+	cascade := Cascade{Origin: cn, Disease: dt, Chained: []CityName{}}
+	outbroke := Set{}
+	added := map[CityName]int{}
+	liftedQuarantines := Set{}
+	var spread func(city *City)
+	spread = func(city *City) {
+		outbroke.Add(city.Name)
+		for _, neighbor := range gs.Board().NeighborCities(city.Name) {
+			if outbroke.Contains(neighbor.Name) || gs.medicProtects(neighbor.Name, dt) {
+				continue
+			}
+			if neighbor.Quarantined && !liftedQuarantines.Contains(neighbor.Name) {
+				if !gs.abilityPresent(neighbor.Name, HoldsQuarantines) {
+					liftedQuarantines.Add(neighbor.Name)
+				}
+				continue
+			}
+			if neighbor.CubesOf(dt)+added[neighbor.Name] == 3 {
+				cascade.Chained = append(cascade.Chained, neighbor.Name)
+				spread(neighbor)
+				continue
+			}
+			added[neighbor.Name]++
+			cascade.Cubes++
+		}
+	}
+	spread(origin)
+	return cascade, nil
+}
+
+// ChainRisk is the chance of a city outbreaking with its own disease during
+// the coming turn, and the cascade it would start.
+type ChainRisk struct {
+	Cascade
+	Probability float64
+}
+
+func (r ChainRisk) ExpectedOutbreaks() float64 {
+	return r.Probability * float64(r.Outbreaks())
+}
+
+func (r ChainRisk) ExpectedCubes() float64 {
+	return r.Probability * float64(r.Cubes)
+}
+
+// ChainRisks lists every city that can outbreak during the coming turn,
+// the most outbreaks expected first.
+func (gs *GameState) ChainRisks() []ChainRisk {
+	risks := []ChainRisk{}
+	for _, city := range gs.Board().cities {
+		p := gs.InfectionOdds(city.Name).Outbreak
+		if p == 0.0 {
+			continue
+		}
+		cascade, err := gs.PredictCascade(city.Name, city.Disease)
+		if err != nil {
+			continue
+		}
+		risks = append(risks, ChainRisk{Cascade: cascade, Probability: p})
+	}
+	sort.SliceStable(risks, func(i, j int) bool {
+		return risks[i].ExpectedOutbreaks() > risks[j].ExpectedOutbreaks()
+	})
+	return risks
+}
+
+// ChainCluster is a group of connected cities holding 3 cubes of the same
+// disease, which all outbreak once one of them does, or a single city that
+// can outbreak without being in such a group, even if its cascade reaches
+// one. Probability is the chance that one of the cities outbreaks during the
+// coming turn, taking them as independent. Outbreaks and Cubes are those of
+// the longest cascade any of them starts, and the expected outbreaks and
+// cubes are that cascade's at the cluster's probability.
+type ChainCluster struct {
+	Disease           DiseaseType
+	Cities            []CityName
+	Probability       float64
+	Outbreaks         int
+	Cubes             int
+	ExpectedOutbreaks float64
+	ExpectedCubes     float64
+}
+
+// saturatedClusters groups the cities with 3 cubes of a disease with their
+// neighbors that also have 3.
+func (gs *GameState) saturatedClusters() []*ChainCluster {
+	clusters := []*ChainCluster{}
+	board := gs.Board()
+	for _, data := range gs.DiseaseData {
+		seen := Set{}
+		for _, city := range board.cities {
+			if city.CubesOf(data.Type) != 3 || seen.Contains(city.Name) {
+				continue
+			}
+			cluster := &ChainCluster{Disease: data.Type}
+			seen.Add(city.Name)
+			queue := []*City{city}
+			for len(queue) > 0 {
+				cur := queue[0]
+				queue = queue[1:]
+				cluster.Cities = append(cluster.Cities, cur.Name)
+				for _, neighbor := range board.NeighborCities(cur.Name) {
+					if neighbor.CubesOf(data.Type) == 3 && !seen.Contains(neighbor.Name) {
+						seen.Add(neighbor.Name)
+						queue = append(queue, neighbor)
+					}
+				}
+			}
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+func (c *ChainCluster) contains(cn CityName) bool {
+	for _, city := range c.Cities {
+		if city == cn {
+			return true
+		}
+	}
+	return false
+}
+
+// startedBy keeps the cascade if it is the longest any of the cluster's
+// cities starts.
+func (c *ChainCluster) startedBy(cascade Cascade) {
+	if cascade.Outbreaks() > c.Outbreaks || (cascade.Outbreaks() == c.Outbreaks && cascade.Cubes > c.Cubes) {
+		c.Outbreaks = cascade.Outbreaks()
+		c.Cubes = cascade.Cubes
+	}
+}
+
+// ChainClusters lists the clusters that can outbreak during the coming
+// turn, the most outbreaks expected first. A city outside of a cluster that
+// would chain into it is listed on its own, with its whole cascade.
+func (gs *GameState) ChainClusters() []ChainCluster {
+	clusters := gs.saturatedClusters()
+	noChance := make([]float64, len(clusters))
+	for i := range noChance {
+		noChance[i] = 1.0
+	}
+	for _, risk := range gs.ChainRisks() {
+		started := false
+		for i, cluster := range clusters {
+			if cluster.Disease == risk.Disease && cluster.contains(risk.Origin) {
+				noChance[i] *= 1.0 - risk.Probability
+				cluster.startedBy(risk.Cascade)
+				started = true
+				break
+			}
+		}
+		if !started {
+			cluster := &ChainCluster{Disease: risk.Disease, Cities: []CityName{risk.Origin}}
+			cluster.startedBy(risk.Cascade)
+			clusters = append(clusters, cluster)
+			noChance = append(noChance, 1.0-risk.Probability)
+		}
+	}
+	ret := []ChainCluster{}
+	for i, cluster := range clusters {
+		if cluster.Outbreaks == 0 {
+			continue
+		}
+		cluster.Probability = 1.0 - noChance[i]
+		cluster.ExpectedOutbreaks = cluster.Probability * float64(cluster.Outbreaks)
+		cluster.ExpectedCubes = cluster.Probability * float64(cluster.Cubes)
+		ret = append(ret, *cluster)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].ExpectedOutbreaks > ret[j].ExpectedOutbreaks
+	})
+	return ret
+}
@@ -0,0 +1,97 @@
+package pandemic
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPredictCascadeMatchesOutbreak(t *testing.T) {
+	gs := newTestGame(t)
+	for _, cn := range []CityName{"atlanta", "chicago", "washington", "montreal"} {
+		if err := gs.SetInfections(cn, Blue.Type, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gs.SetInfections("miami", Blue.Type, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Quarantine("newyork"); err != nil {
+		t.Fatal(err)
+	}
+
+	cascade, err := gs.PredictCascade("atlanta", Blue.Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := gs.Infect("atlanta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cubes := 0
+	for _, step := range result.Chain {
+		if step.Effect == CubeAdded {
+			cubes++
+		}
+	}
+	if !reflect.DeepEqual(cascade.Chained, result.Chained) || cascade.Cubes != cubes || cascade.Outbreaks() != result.Outbreaks() {
+		t.Fatalf("Predicted %+v, but the outbreak was:\n%v", cascade, result)
+	}
+}
+
+func TestChainClusters(t *testing.T) {
+	gs := newTestGame(t)
+	for _, cn := range []CityName{"atlanta", "chicago", "washington"} {
+		if err := gs.SetInfections(cn, Blue.Type, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// montreal can only outbreak from an epidemic, chaining into the cluster
+	if err := gs.SetInfections("montreal", Blue.Type, 1); err != nil {
+		t.Fatal(err)
+	}
+	clusters := gs.ChainClusters()
+	if len(clusters) == 0 || len(clusters[0].Cities) != 3 {
+		t.Fatalf("Expected the atlanta cluster to be the biggest risk, got %+v", clusters)
+	}
+	top := clusters[0]
+	noChance := 1.0
+	longest := Cascade{}
+	for _, risk := range gs.ChainRisks() {
+		if risk.Origin == "atlanta" && (risk.Outbreaks() != 3 || risk.Probability != gs.InfectionOdds("atlanta").Outbreak) {
+			t.Fatalf("Unexpected risk of atlanta: %+v", risk)
+		}
+		if top.contains(risk.Origin) {
+			noChance *= 1.0 - risk.Probability
+			if risk.Outbreaks() > longest.Outbreaks() || (risk.Outbreaks() == longest.Outbreaks() && risk.Cubes > longest.Cubes) {
+				longest = risk.Cascade
+			}
+		}
+	}
+	if top.Probability <= 0 || math.Abs(top.Probability-(1.0-noChance)) > 1e-9 {
+		t.Fatalf("Expected the chance of any of the cities outbreaking, got %+v", top)
+	}
+	// every city of the cluster starts the same 3 outbreaks, which should be
+	// expected once rather than once per city
+	if top.Outbreaks != 3 || top.Cubes != longest.Cubes || math.Abs(top.ExpectedOutbreaks-3*top.Probability) > 1e-9 ||
+		math.Abs(top.ExpectedCubes-float64(longest.Cubes)*top.Probability) > 1e-9 {
+		t.Fatalf("Expected the cascade of the cluster at its probability, got %+v", top)
+	}
+
+	montreal, err := gs.PredictCascade("montreal", Blue.Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := gs.InfectionOdds("montreal").Outbreak
+	for _, cluster := range clusters {
+		if !cluster.contains("montreal") {
+			continue
+		}
+		if len(cluster.Cities) != 1 || math.Abs(cluster.Probability-p) > 1e-9 || cluster.Outbreaks != montreal.Outbreaks() || montreal.Outbreaks() != 4 ||
+			math.Abs(cluster.ExpectedOutbreaks-4*cluster.Probability) > 1e-9 || math.Abs(cluster.ExpectedCubes-float64(montreal.Cubes)*cluster.Probability) > 1e-9 {
+			t.Fatalf("Expected montreal on its own with its whole cascade, got %+v", cluster)
+		}
+		return
+	}
+	t.Fatalf("Expected montreal to be listed, got %+v", clusters)
+}
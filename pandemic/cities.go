@@ -50,14 +50,18 @@ type CityCard struct {
 	FundedEventName FundedEventName `json:"funded_event_name,omitempty"`
 }
 
+// City is a city on the board. Cubes counts the cubes of each disease on
+// the city, since outbreaks of a neighbor's disease can leave cubes of a
+// colour other than the city's own.
 type City struct {
-	Name            CityName    `json:"name"`
-	Disease         DiseaseType `json:"disease"`
-	OriginalDisease DiseaseType `json:"original_disease"`
-	PanicLevel      PanicLevel  `json:"panic_level"`
-	Neighbors       []string    `json:"neighbors"`
-	NumInfections   int         `json:"num_infections"`
-	Quarantined     bool        `json:"quarantined"`
+	Name            CityName            `json:"name"`
+	Disease         DiseaseType         `json:"disease"`
+	OriginalDisease DiseaseType         `json:"original_disease"`
+	PanicLevel      PanicLevel          `json:"panic_level"`
+	Neighbors       []string            `json:"neighbors"`
+	Cubes           map[DiseaseType]int `json:"cubes,omitempty"`
+	Quarantined     bool                `json:"quarantined"`
+	ResearchStation bool                `json:"research_station,omitempty"`
 }
 
 type Cities []*City
@@ -164,20 +168,64 @@ func (c Cities) CityNames() []CityName {
 	return names
 }
 
+// countsAs is true if the city's card counts towards the disease: its
+// original disease, or the disease it has changed into.
+func (c *City) countsAs(dt DiseaseType) bool {
+	return c.OriginalDisease == dt || (c.Disease == dt && c.Disease != c.OriginalDisease)
+}
+
+// CubesOf is the number of cubes of the disease on the city.
+func (c *City) CubesOf(dt DiseaseType) int {
+	return c.Cubes[dt]
+}
+
+// TotalCubes is the number of cubes of every disease on the city.
+func (c *City) TotalCubes() int {
+	total := 0
+	for _, cubes := range c.Cubes {
+		total += cubes
+	}
+	return total
+}
+
+// MaxCubes is the highest number of cubes of any one disease on the city,
+// which is how close the city is to an outbreak.
+func (c *City) MaxCubes() int {
+	max := 0
+	for _, cubes := range c.Cubes {
+		if cubes > max {
+			max = cubes
+		}
+	}
+	return max
+}
+
+// Infect adds a cube of the city's own disease, returning true instead if
+// the city outbreaks.
 func (c *City) Infect() bool {
-	if c.NumInfections == 3 {
+	return c.InfectWith(c.Disease)
+}
+
+// InfectWith adds a cube of the given disease, returning true instead if
+// the city already has 3 cubes of it and outbreaks.
+func (c *City) InfectWith(dt DiseaseType) bool {
+	if c.CubesOf(dt) == 3 {
 		return true
 	}
-	c.NumInfections++
+	c.SetInfections(dt, c.CubesOf(dt)+1)
 	return false
 }
 
 func (c *City) Epidemic() bool {
-	ret := c.NumInfections > 0
-	c.NumInfections = 3
+	ret := c.CubesOf(c.Disease) > 0
+	c.SetInfections(c.Disease, 3)
 	return ret
 }
 
+func (c *City) RaisePanic() {
+	c.PanicLevel = c.PanicLevel.Raise()
+}
+
 func (c *City) Quarantine() {
 	c.Quarantined = true
 }
@@ -186,15 +234,29 @@ func (c *City) RemoveQuarantine() {
 	c.Quarantined = false
 }
 
-func (c *City) SetInfections(infections int) {
-	c.NumInfections = infections
+// IsNeighbor is true if the city is connected to the other city.
+func (c *City) IsNeighbor(cn CityName) bool {
+	for _, neighbor := range c.Neighbors {
+		if CityName(neighbor) == cn {
+			return true
+		}
+	}
+	return false
 }
 
-func (c *City) TreatInfections(infections int) {
-	c.NumInfections -= infections
-	if c.NumInfections < 0 {
-		c.NumInfections = 0
+func (c *City) SetInfections(dt DiseaseType, infections int) {
+	if c.Cubes == nil {
+		c.Cubes = map[DiseaseType]int{}
 	}
+	if infections <= 0 {
+		delete(c.Cubes, dt)
+		return
+	}
+	c.Cubes[dt] = infections
+}
+
+func (c *City) TreatInfections(dt DiseaseType, infections int) {
+	c.SetInfections(dt, c.CubesOf(dt)-infections)
 }
 
 func (c CityDeck) Total() int {
@@ -234,9 +296,9 @@ func (c *CityDeck) ProbabilityOfDrawing(cn CardName) float64 {
 	return 1.0 / float64(len(c.All)-len(c.Drawn))
 }
 
-// Returns the probability of drawing a particular type. If the given
-// disease type is Faded, will compare against the current disease instead
-// of the original disease.
+// Returns the probability of drawing a particular type. Cards count as
+// their original disease, and as the disease they changed into, such as
+// Faded.
 func (c *CityDeck) ProbabilityOfDrawingType(dt DiseaseType, cities *Cities) float64 {
 	inAll := c.RemainingCardsWith(dt, cities)
 	return float64(inAll) / (float64(c.RemainingCards()))
@@ -253,11 +315,7 @@ func (c *CityDeck) RemainingCardsWith(dt DiseaseType, cities *Cities) int {
 			continue
 		}
 		city, _ := cities.GetCity(card.CityName)
-		toCompare := city.OriginalDisease
-		if dt == Faded.Type {
-			toCompare = city.Disease
-		}
-		if toCompare == dt {
+		if city.countsAs(dt) {
 			inAll++
 		}
 	}
@@ -266,11 +324,7 @@ func (c *CityDeck) RemainingCardsWith(dt DiseaseType, cities *Cities) int {
 			continue
 		}
 		city, _ := cities.GetCity(card.CityName)
-		toCompare := city.OriginalDisease
-		if dt == Faded.Type {
-			toCompare = city.Disease
-		}
-		if toCompare == dt {
+		if city.countsAs(dt) {
 			inAll--
 		}
 	}
@@ -420,6 +474,7 @@ type EpidemicAnalysis struct {
 	FirstCardProbability       float64
 	SecondCardProbability      float64
 	SecondCardEpiAfterFirstEpi float64
+	SecondCardEpiAfterCity     float64
 	PossibleScenarios          int
 	ScenariosWith100           int
 	ComingDrawsWith0           int
@@ -518,6 +573,7 @@ func (c *cityDeckProbabilityModel) EpidemicAnalysis(index int) EpidemicAnalysis
 	analysis.SecondCardProbability = analysis.FirstCardProbability*epiOnSecondAndFirst +
 		(1.0-analysis.FirstCardProbability)*epiOnSecondNotFirst
 	analysis.SecondCardEpiAfterFirstEpi = epiOnSecondAndFirst
+	analysis.SecondCardEpiAfterCity = epiOnSecondNotFirst
 	var zeroCount int
 	for i := index; i <= c.HighestIndex(); i++ {
 		if c.EpidemicProbabilityAt(i) == 0.0 {
@@ -3,7 +3,6 @@ package pandemic
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"math"
 	"path/filepath"
@@ -13,14 +12,20 @@ import (
 func TestLoadFromJSON(t *testing.T) {
 
 	// Do IO
-	filename, _ := filepath.Abs("../data/cities.json")
-	data, _ := ioutil.ReadFile(filename)
-	c := Cities{}
+	filename, _ := filepath.Abs("../data/new_game.json")
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := NewGameSettings{}
 
 	// Decode JSON
 	dec := json.NewDecoder(bytes.NewReader(data))
-	if err := dec.Decode(&c); err != nil {
-		fmt.Errorf("Decode city: %v", err)
+	if err := dec.Decode(&settings); err != nil {
+		t.Fatalf("Decode city: %v", err)
+	}
+	if len(settings.Cities) != 48 {
+		t.Fatalf("Expected 48 cities, got %v", len(settings.Cities))
 	}
 
 }
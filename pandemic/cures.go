@@ -0,0 +1,185 @@
+package pandemic
+
+import "fmt"
+
+// CureStatus is how far the players got against a disease.
+type CureStatus string
+
+const (
+	Uncured    CureStatus = ""
+	Cured      CureStatus = "cured"
+	Eradicated CureStatus = "eradicated"
+)
+
+// CureTrack records the status of every disease that has been cured.
+type CureTrack map[DiseaseType]CureStatus
+
+// CardsToCureDisease is the number of city cards of a disease a player must
+// discard to cure it in the base game, before character modifiers.
+const CardsToCureDisease = 5
+
+func (gs *GameState) CureStatus(dt DiseaseType) CureStatus {
+	return gs.Cures[dt]
+}
+
+// IsCured is true for cured and eradicated diseases.
+func (gs *GameState) IsCured(dt DiseaseType) bool {
+	return gs.Cures[dt] != Uncured
+}
+
+func (gs *GameState) IsEradicated(dt DiseaseType) bool {
+	return gs.Cures[dt] == Eradicated
+}
+
+// CureModifier changes the number of cards a character needs to cure a
+// disease.
+type CureModifier struct {
+	Cards      int  `json:"cards,omitempty"`
+	CannotCure bool `json:"cannot_cure,omitempty"`
+}
+
+// CureModifiers override the cure modifiers of the characters' abilities.
+type CureModifiers map[CharacterType]CureModifier
+
+// CureModifierOf is the cure modifier of the player's character, taken from
+// the game file if it sets one and from the character's abilities otherwise.
+func (gs *GameState) CureModifierOf(player *Player) CureModifier {
+	if player.Character == nil {
+		return CureModifier{}
+	}
+	if modifier, ok := gs.CureModifiers[player.Character.Type]; ok {
+		return modifier
+	}
+	return player.Abilities().CureModifier()
+}
+
+// CardsToCure is the number of city cards the player has to discard to cure
+// a disease.
+func (gs *GameState) CardsToCure(player *Player, dt DiseaseType) (int, error) {
+	required := gs.DataForDisease(dt).RequiredToCure()
+	modifier := gs.CureModifierOf(player)
+	if modifier.CannotCure {
+		return 0, fmt.Errorf("%v is a %v and cannot cure diseases", player.HumanName, player.Character.Type)
+	}
+	required += modifier.Cards
+	if required < 1 {
+		required = 1
+	}
+	return required, nil
+}
+
+// cureCards lists the cards in the player's hand that count towards curing
+// the disease.
+func (gs *GameState) cureCards(player *Player, dt DiseaseType) []CardName {
+	cards := []CardName{}
+	for _, card := range player.Cards {
+		if !card.IsCity() {
+			continue
+		}
+		city, err := gs.GetCity(card.CityName)
+		if err != nil {
+			continue
+		}
+		if city.Disease == dt && city.PanicLevel.CityCardsUsable() {
+			cards = append(cards, card.Name())
+		}
+	}
+	return cards
+}
+
+// Cure discards the cards from the player's hand and marks the disease as
+// cured. Without cards the player's cards of the disease are used, as long
+// as they hold exactly as many as they need.
+func (gs *GameState) Cure(player *Player, dt DiseaseType, cards []CardName) error {
+	if gs.DataForDisease(dt).Incurable {
+		return fmt.Errorf("%v cannot be cured", dt)
+	}
+	if gs.IsCured(dt) {
+		return fmt.Errorf("%v is already %v", dt, gs.CureStatus(dt))
+	}
+	if err := gs.requireAction("discover a cure"); err != nil {
+		return err
+	}
+	if err := gs.requireStation(player); err != nil {
+		return err
+	}
+	required, err := gs.CardsToCure(player, dt)
+	if err != nil {
+		return err
+	}
+	usable := gs.cureCards(player, dt)
+	if len(cards) == 0 {
+		if len(usable) < required {
+			return fmt.Errorf("%v needs %v %v cards to cure, but only holds %v", player.HumanName, required, dt, len(usable))
+		}
+		if len(usable) > required {
+			return fmt.Errorf("%v holds %v %v cards, choose the %v to discard", player.HumanName, len(usable), dt, required)
+		}
+		cards = usable
+	}
+	if len(cards) != required {
+		return fmt.Errorf("%v needs to discard %v %v cards to cure, not %v", player.HumanName, required, dt, len(cards))
+	}
+	available := Set{}
+	for _, card := range usable {
+		available.Add(card)
+	}
+	for _, card := range cards {
+		if _, ok := available.Remove(card); !ok {
+			return fmt.Errorf("%v cannot discard %v to cure %v", player.HumanName, card, dt)
+		}
+	}
+
+	for _, card := range cards {
+		if err := player.Discard(card); err != nil {
+			return err
+		}
+	}
+	if gs.Cures == nil {
+		gs.Cures = CureTrack{}
+	}
+	gs.Cures[dt] = Cured
+	for _, medic := range gs.playersWith(TreatsCuredOnEntry) {
+		gs.medicTreat(medic.Location)
+	}
+	gs.checkEradication(dt)
+	gs.useAction()
+	gs.record(Event{Type: CureEvent, Player: player.HumanName, Disease: dt, Cards: cards})
+	return nil
+}
+
+// checkEradication marks a cured disease as eradicated once none of its
+// cubes are left on the board.
+func (gs *GameState) checkEradication(dt DiseaseType) {
+	if gs.CureStatus(dt) != Cured {
+		return
+	}
+	for _, city := range *gs.Cities {
+		if city.CubesOf(dt) > 0 {
+			return
+		}
+	}
+	gs.Cures[dt] = Eradicated
+}
+
+// medicProtects is true if a Medic in the city keeps cubes of a cured
+// disease from being placed there.
+func (gs *GameState) medicProtects(cn CityName, dt DiseaseType) bool {
+	return gs.IsCured(dt) && gs.abilityPresent(cn, PreventsCuredCubes)
+}
+
+// medicTreat removes every cube of a cured disease from a city the Medic is
+// in.
+func (gs *GameState) medicTreat(cn CityName) {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return
+	}
+	for dt := range gs.Cures {
+		if before := city.CubesOf(dt); before > 0 {
+			city.SetInfections(dt, 0)
+			gs.placeCubes(city, dt, before)
+			gs.checkEradication(dt)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package pandemic
+
+import "testing"
+
+func TestCureDiscardsCardsAndMedicTreats(t *testing.T) {
+	gs := newTestGame(t)
+	player := gs.GameTurns.PlayerOrder[0]
+	cards := []CardName{"chicago", "washington", "montreal", "newyork", "london", "paris"}
+	for _, cn := range cards {
+		if err := gs.DrawCard(cn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, cn := range []CityName{"atlanta", "essen"} {
+		if err := gs.SetInfections(cn, "", 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gs.Cure(player, Blue.Type, nil); err == nil {
+		t.Fatal("Expected an error when holding more cards than are needed")
+	}
+	if err := gs.Cure(player, Blue.Type, cards[:4]); err == nil {
+		t.Fatal("Expected an error when discarding too few cards")
+	}
+	if err := gs.Cure(player, Blue.Type, cards[:5]); err != nil {
+		t.Fatal(err)
+	}
+	if len(player.Cards) != 1 || player.Cards[0].Name() != "paris" {
+		t.Fatalf("Expected only paris to be left in hand, got %v", player.Cards)
+	}
+	if gs.CureStatus(Blue.Type) != Cured {
+		t.Fatalf("Expected blue to be cured, got %q", gs.CureStatus(Blue.Type))
+	}
+	atlanta, _ := gs.GetCity("atlanta")
+	if atlanta.TotalCubes() != 0 {
+		t.Fatal("The medic in atlanta should treat blue as soon as it is cured")
+	}
+	if _, err := gs.Infect("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if atlanta.TotalCubes() != 0 {
+		t.Fatal("The medic should keep cured cubes out of atlanta")
+	}
+	if err := gs.Cure(player, Blue.Type, nil); err == nil {
+		t.Fatal("Expected an error curing blue twice")
+	}
+
+	if err := gs.TreatInfections("essen", "", 2); err != nil {
+		t.Fatal(err)
+	}
+	if !gs.IsEradicated(Blue.Type) {
+		t.Fatal("Expected blue to be eradicated once the last cube was treated")
+	}
+	if _, err := gs.Infect("chicago"); err != nil {
+		t.Fatal(err)
+	}
+	chicago, _ := gs.GetCity("chicago")
+	if chicago.TotalCubes() != 0 {
+		t.Fatal("Eradicated diseases should not be placed")
+	}
+
+	loaded := roundTrip(t, gs)
+	if !loaded.IsEradicated(Blue.Type) {
+		t.Fatal("The cure status should survive saving and loading")
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := gs.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gs.IsCured(Blue.Type) || len(gs.GameTurns.PlayerOrder[0].Cards) != 6 {
+		t.Fatal("Undoing the cure should return the cards and the disease status")
+	}
+	if _, _, err := gs.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if !gs.IsCured(Blue.Type) {
+		t.Fatal("Redoing the cure should cure blue again")
+	}
+}
+
+func TestCardsToCure(t *testing.T) {
+	gs := newTestGame(t)
+	player := gs.GameTurns.PlayerOrder[0]
+	cases := map[CharacterType]int{
+		ContingencyPlanner: CardsToCureDisease,
+		Scientist:          CardsToCureDisease - 1,
+	}
+	for ct, expected := range cases {
+		player.Character.Type = ct
+		required, err := gs.CardsToCure(player, Blue.Type)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if required != expected {
+			t.Fatalf("Expected %v to need %v cards, got %v", ct, expected, required)
+		}
+	}
+	player.Character.Type = Soldier
+	if _, err := gs.CardsToCure(player, Blue.Type); err == nil {
+		t.Fatal("Soldiers should not be able to cure")
+	}
+}
+
+func TestCureModifiersFromSettings(t *testing.T) {
+	settings := boardSettings(t)
+	settings.Diseases = Diseases{Yellow, Red, Black, {Type: "Blue", CardsToCure: 4}}
+	settings.CureModifiers = CureModifiers{
+		Scientist:          {Cards: -2},
+		ContingencyPlanner: {CannotCure: true},
+	}
+	gs, err := NewGameFromSettings(settings, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := gs.GameTurns.PlayerOrder[0]
+	if _, err := gs.CardsToCure(player, Blue.Type); err == nil {
+		t.Fatal("The game file should be able to stop a character from curing")
+	}
+	if gs.ProbabilityOfCuring(player, Blue.Type) != 0 {
+		t.Fatal("Characters that cannot cure should have no chance of curing")
+	}
+	cases := map[CharacterType]int{
+		Scientist: 2,
+		Colonel:   6,
+		Medic:     4,
+	}
+	for ct, expected := range cases {
+		player.Character.Type = ct
+		if required, _ := gs.CardsToCure(player, Blue.Type); required != expected {
+			t.Fatalf("Expected %v to need %v blue cards, got %v", ct, expected, required)
+		}
+	}
+	player.Character.Type = Medic
+	if required, _ := gs.CardsToCure(player, Yellow.Type); required != CardsToCureDisease {
+		t.Fatalf("Expected yellow to need %v cards, got %v", CardsToCureDisease, required)
+	}
+
+	loaded := roundTrip(t, gs)
+	player = loaded.GameTurns.PlayerOrder[0]
+	player.Character.Type = Scientist
+	if required, _ := loaded.CardsToCure(player, Blue.Type); required != 2 {
+		t.Fatal("The cure modifiers should survive saving and loading")
+	}
+}
@@ -1,32 +1,44 @@
 package pandemic
 
+import "fmt"
+
 type DiseaseType string
 
+// DiseaseData describes one disease of a game. Cubes and CardsToCure fall
+// back to the base game's rules when they are not set.
 type DiseaseData struct {
 	Type             DiseaseType `json:"type"`
+	Name             string      `json:"name,omitempty"`
+	Icon             string      `json:"icon,omitempty"`
+	Cubes            int         `json:"cubes,omitempty"`
+	CardsToCure      int         `json:"cards_to_cure,omitempty"`
 	Incurable        bool        `json:"incurable,omitempty"`
 	Untreatable      bool        `json:"untreatable,omitempty"`
 	BecomingFaded    bool        `json:"becoming_faded,omitempty"`
 	InfectOnCityDraw bool        `json:"infect_on_city_draw,omitempty"`
 }
 
+// The diseases of the base game, used when the new game file does not
+// define its own.
 var Yellow = DiseaseData{
 	Type: DiseaseType("Yellow"),
+	Icon: "\U0001f49b",
 }
 var Blue = DiseaseData{
 	Type: DiseaseType("Blue"),
-	//Incurable:     true, // TODO: make configurable with a gamestate
-	//Untreatable:   true,
-	//BecomingFaded: true,
+	Icon: "\U0001f499",
 }
 var Red = DiseaseData{
 	Type: DiseaseType("Red"),
+	Icon: "\U0001F6D1",
 }
 var Black = DiseaseData{
 	Type: DiseaseType("Black"),
+	Icon: "\u26ab",
 }
 var Faded = DiseaseData{
 	Type:             DiseaseType("Faded"),
+	Icon:             "\U0001f608",
 	Incurable:        true,
 	Untreatable:      true,
 	BecomingFaded:    true,
@@ -37,28 +49,123 @@ func (dt DiseaseType) String() string {
 	return string(dt)
 }
 
-var diseaseDataMap map[DiseaseType]DiseaseData
+func (d DiseaseData) DisplayName() string {
+	if d.Name != "" {
+		return d.Name
+	}
+	return string(d.Type)
+}
+
+// DisplayIcon falls back to the name of the disease if it has no icon.
+func (d DiseaseData) DisplayIcon() string {
+	if d.Icon != "" {
+		return d.Icon
+	}
+	return d.DisplayName()
+}
+
+// TotalCubes is the number of cubes of the disease in the box.
+func (d DiseaseData) TotalCubes() int {
+	if d.Cubes > 0 {
+		return d.Cubes
+	}
+	return CubesPerDisease
+}
+
+// RequiredToCure is the number of city cards needed to cure the disease,
+// before character modifiers.
+func (d DiseaseData) RequiredToCure() int {
+	if d.CardsToCure > 0 {
+		return d.CardsToCure
+	}
+	return CardsToCureDisease
+}
+
+// Diseases are the diseases in play in one game, in the order they were
+// defined.
+type Diseases []DiseaseData
+
+func DefaultDiseases() Diseases {
+	return Diseases{Yellow, Red, Black, Blue, Faded}
+}
 
-func init() {
-	diseaseDataMap = map[DiseaseType]DiseaseData{
-		Yellow.Type: Yellow,
-		Blue.Type:   Blue,
-		Red.Type:    Red,
-		Black.Type:  Black,
-		Faded.Type:  Faded,
+// Get returns the definition of the disease. Diseases that are not part of
+// the game get a definition with the default rules.
+func (d Diseases) Get(dt DiseaseType) DiseaseData {
+	for _, data := range d {
+		if data.Type == dt {
+			return data
+		}
 	}
+	return DiseaseData{Type: dt}
 }
 
-func DataForDisease(dt DiseaseType) DiseaseData {
-	return diseaseDataMap[dt]
+func (d Diseases) Contains(dt DiseaseType) bool {
+	for _, data := range d {
+		if data.Type == dt {
+			return true
+		}
+	}
+	return false
 }
 
-func CurableDiseases() []DiseaseType {
+func (d Diseases) Types() []DiseaseType {
 	ret := []DiseaseType{}
-	for dt, data := range diseaseDataMap {
+	for _, data := range d {
+		ret = append(ret, data.Type)
+	}
+	return ret
+}
+
+func (d Diseases) Curable() []DiseaseType {
+	ret := []DiseaseType{}
+	for _, data := range d {
 		if !data.Incurable {
-			ret = append(ret, dt)
+			ret = append(ret, data.Type)
 		}
 	}
 	return ret
 }
+
+// Faded is the disease cities turn into when they fade, or an empty type if
+// no disease in the game does.
+func (d Diseases) Faded() DiseaseType {
+	for _, data := range d {
+		if data.BecomingFaded {
+			return data.Type
+		}
+	}
+	return ""
+}
+
+// Validate checks that every disease is defined once and that every city
+// has one of them.
+func (d Diseases) Validate(cities Cities) error {
+	seen := map[DiseaseType]bool{}
+	for _, data := range d {
+		if data.Type == "" {
+			return fmt.Errorf("Every disease must have a type")
+		}
+		if seen[data.Type] {
+			return fmt.Errorf("Disease %v is defined more than once", data.Type)
+		}
+		if data.Cubes < 0 || data.CardsToCure < 0 {
+			return fmt.Errorf("Disease %v cannot have a negative number of cubes or cards to cure", data.Type)
+		}
+		seen[data.Type] = true
+	}
+	for _, city := range cities {
+		if !seen[city.Disease] {
+			return fmt.Errorf("%v has the disease %v, which is not defined for this game", city.Name, city.Disease)
+		}
+	}
+	return nil
+}
+
+func (gs *GameState) DataForDisease(dt DiseaseType) DiseaseData {
+	return gs.DiseaseData.Get(dt)
+}
+
+func (gs *GameState) CurableDiseases() []DiseaseType {
+	return gs.DiseaseData.Curable()
+}
@@ -0,0 +1,87 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+func boardSettings(t *testing.T) *NewGameSettings {
+	data, err := ioutil.ReadFile("../data/pandemicboard.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings NewGameSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	return &settings
+}
+
+func TestGameDiseasesFromSettings(t *testing.T) {
+	settings := boardSettings(t)
+	settings.Diseases = Diseases{
+		{Type: "Yellow", Cubes: 12},
+		{Type: "Red", Incurable: true},
+		{Type: "Black", Untreatable: true},
+		{Type: "Blue", Name: "Virulent Blue", CardsToCure: 4},
+	}
+	gs, err := NewGameFromSettings(settings, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs.DiseaseSupply[Yellow.Type] != 12 || gs.DiseaseSupply[Blue.Type] != CubesPerDisease {
+		t.Fatalf("Expected the supply to follow the disease cube counts, got %v", gs.DiseaseSupply)
+	}
+	if _, ok := gs.DiseaseSupply[Faded.Type]; ok {
+		t.Fatal("Diseases that are not part of the game should not be in the supply")
+	}
+	if required, _ := gs.CardsToCure(gs.GameTurns.PlayerOrder[0], Blue.Type); required != 4 {
+		t.Fatalf("Expected blue to need 4 cards to cure, got %v", required)
+	}
+	if len(gs.CurableDiseases()) != 3 {
+		t.Fatalf("Expected red to be the only incurable disease, got %v", gs.CurableDiseases())
+	}
+	if err := gs.Cure(gs.GameTurns.PlayerOrder[0], Red.Type, nil); err == nil {
+		t.Fatal("Incurable diseases cannot be cured")
+	}
+	if err := gs.SetInfections("baghdad", "", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.TreatInfections("baghdad", "", 1); err == nil {
+		t.Fatal("Untreatable diseases cannot be treated")
+	}
+	if gs.DataForDisease(Blue.Type).DisplayName() != "Virulent Blue" || gs.DataForDisease(Blue.Type).DisplayIcon() != "Virulent Blue" {
+		t.Fatal("Diseases without an icon should be shown by name")
+	}
+
+	rebuilt, err := gs.Journal.rebuild("test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt.DataForDisease(Yellow.Type).TotalCubes() != 12 {
+		t.Fatal("The disease definitions should be part of the saved setup")
+	}
+}
+
+func TestDiseasesAreValidated(t *testing.T) {
+	settings := boardSettings(t)
+	settings.Diseases = Diseases{Yellow, Red, Black}
+	if _, err := NewGameFromSettings(settings, "test"); err == nil {
+		t.Fatal("Expected an error for cities with an undefined disease")
+	}
+	settings.Diseases = Diseases{Yellow, Red, Black, Blue, Blue}
+	if _, err := NewGameFromSettings(settings, "test"); err == nil {
+		t.Fatal("Expected an error for a disease defined twice")
+	}
+}
+
+func TestLegacyBoardDefinesItsDiseases(t *testing.T) {
+	gs, err := NewGame("../data/legacy1.json", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs.DiseaseData.Faded() != Faded.Type || !gs.DataForDisease(Faded.Type).InfectOnCityDraw {
+		t.Fatal("Expected the faded disease to be read from the new game file")
+	}
+}
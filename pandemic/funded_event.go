@@ -1,5 +1,88 @@
 package pandemic
 
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxFundingLevel is the highest funding level on the Legacy funding track.
+const MaxFundingLevel = 10
+
 type FundedEvent struct {
-	Name FundedEventName `json:"name"`
+	Name   FundedEventName `json:"name"`
+	Effect string          `json:"effect,omitempty"`
+}
+
+var fundedEventCatalogue = map[FundedEventName]string{
+	"airlift":                "Move any 1 pawn to any city",
+	"forecast":               "Draw, look at and rearrange the top 6 cards of the infection deck",
+	"governmentgrant":        "Add 1 research station to any city",
+	"onequietnight":          "Skip the next infect cities step",
+	"resilientpopulation":    "Remove any 1 card in the infection discard pile from the game",
+	"improvedsanitation":     "Remove up to 3 cubes from cities connected to a city with a research station",
+	"infectionzoneban":       "Place a quarantine marker in any city",
+	"sequencingbreakthrough": "Place a cure marker on any cure space",
+	"remotetreatment":        "Remove up to 2 cubes from anywhere on the board",
+	"reexaminedresearch":     "Take a city card from the player discard pile into your hand",
+}
+
+// FundedEventCatalogue lists every known funded event, sorted by name.
+func FundedEventCatalogue() []FundedEvent {
+	events := []FundedEvent{}
+	for name, effect := range fundedEventCatalogue {
+		events = append(events, FundedEvent{name, effect})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Name < events[j].Name })
+	return events
+}
+
+// LookupFundedEvent returns the catalogue entry of a funded event.
+func LookupFundedEvent(name FundedEventName) (*FundedEvent, error) {
+	effect, ok := fundedEventCatalogue[name]
+	if !ok {
+		return nil, fmt.Errorf("%v is not a known funded event", name)
+	}
+	return &FundedEvent{name, effect}, nil
+}
+
+// NamedFundedEvent returns the catalogue entry of a funded event, or an
+// event without an effect when the catalogue does not know it.
+func NamedFundedEvent(name FundedEventName) *FundedEvent {
+	if event, err := LookupFundedEvent(name); err == nil {
+		return event
+	}
+	return &FundedEvent{Name: name}
+}
+
+// ValidateFunding checks that the funding level, when one is set, is on the
+// funding track and that there are exactly that many funded events.
+func (s *NewGameSettings) ValidateFunding() error {
+	if s.FundingLevel == nil {
+		return nil
+	}
+	level := *s.FundingLevel
+	if level < 0 || level > MaxFundingLevel {
+		return fmt.Errorf("Funding level must be between 0 and %v, got %v", MaxFundingLevel, level)
+	}
+	if len(s.FundedEvents) != level {
+		return fmt.Errorf("Funding level %v requires %v funded events, got %v", level, level, len(s.FundedEvents))
+	}
+	return nil
+}
+
+// FundingWarnings lists the funded events that are not in the catalogue or
+// are funded more than once. They are still shuffled into the city deck.
+func (s *NewGameSettings) FundingWarnings() []string {
+	warnings := []string{}
+	seen := Set{}
+	for _, event := range s.FundedEvents {
+		if _, err := LookupFundedEvent(event.Name); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+		if seen.Contains(event.Name) {
+			warnings = append(warnings, fmt.Sprintf("%v is funded more than once", event.Name))
+		}
+		seen.Add(event.Name)
+	}
+	return warnings
 }
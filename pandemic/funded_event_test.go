@@ -0,0 +1,46 @@
+package pandemic
+
+import "testing"
+
+func TestValidateFunding(t *testing.T) {
+	two, four := 2, 4
+	events := func(names ...FundedEventName) []*FundedEvent {
+		list := []*FundedEvent{}
+		for _, name := range names {
+			list = append(list, &FundedEvent{Name: name})
+		}
+		return list
+	}
+	cases := []struct {
+		settings NewGameSettings
+		valid    bool
+		warnings int
+	}{
+		{NewGameSettings{FundedEvents: events("airlift", "forecast", "ghostevent")}, true, 1},
+		{NewGameSettings{FundedEvents: events("airlift", "airlift")}, true, 1},
+		{NewGameSettings{FundedEvents: events("airlift", "forecast", "onequietnight")}, true, 0},
+		{NewGameSettings{FundedEvents: events("airlift", "ghostevent"), FundingLevel: &two}, true, 1},
+		{NewGameSettings{FundedEvents: events("airlift", "forecast"), FundingLevel: &four}, false, 0},
+	}
+	for i, c := range cases {
+		err := c.settings.ValidateFunding()
+		if c.valid && err != nil {
+			t.Fatalf("Case %v should be valid: %v", i, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("Case %v should be invalid", i)
+		}
+		if warnings := c.settings.FundingWarnings(); len(warnings) != c.warnings {
+			t.Fatalf("Case %v should have %v warnings, got %v", i, c.warnings, warnings)
+		}
+	}
+}
+
+func TestCatalogueCoversBoardEvents(t *testing.T) {
+	if _, err := NewGame("../data/pandemicboard.json", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if len(FundedEventCatalogue()) != len(fundedEventCatalogue) {
+		t.Fatal("Every catalogue entry should be listed")
+	}
+}
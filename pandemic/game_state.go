@@ -10,18 +10,31 @@ import (
 	"github.com/gmsconstantino/pandemic-nerd-hurd/pandemic/combinations"
 )
 
-const CityCardsPerTurn = 2
+const (
+	CityCardsPerTurn = 2
+	EpidemicsPerGame = 5
+)
 
 type GameState struct {
+	SchemaVersion int            `json:"schema_version"`
 	Cities        *Cities        `json:"cities"`
 	CityDeck      *CityDeck      `json:"city_deck"`
-	DiseaseData   []DiseaseData  `json:"disease_data"`
+	DiseaseData   Diseases       `json:"disease_data"`
 	InfectionDeck *InfectionDeck `json:"infection_deck"`
 	InfectionRate int            `json:"infection_rate"`
 	Outbreaks     int            `json:"outbreaks"`
+	DiseaseSupply DiseaseSupply  `json:"disease_supply"`
+	Cures         CureTrack      `json:"cures,omitempty"`
+	CureModifiers CureModifiers  `json:"cure_modifiers,omitempty"`
+	HandLimit     int            `json:"hand_limit,omitempty"`
+	StationLimit  int            `json:"station_limit,omitempty"`
 	GameName      string         `json:"game_name"`
 	GameTurns     *GameTurns     `json:"game_turns"`
-	IsStarted     bool           `json:"isstarted"`
+	IsStarted     bool           `json:"is_started"`
+	Journal       *Journal       `json:"-"`
+	IgnoreRules   bool           `json:"-"`
+	board         *Board
+	replaying     *Event
 }
 
 type NewGameSettings struct {
@@ -29,17 +42,46 @@ type NewGameSettings struct {
 	Cities           Cities         `json:"cities"`
 	Players          []*Player      `json:"players"`
 	FundedEvents     []*FundedEvent `json:"funded_events"`
+	FundingLevel     *int           `json:"funding_level,omitempty"`
+	Diseases         Diseases       `json:"diseases,omitempty"`
+	CureModifiers    CureModifiers  `json:"cure_modifiers,omitempty"`
+	HandLimit        int            `json:"hand_limit,omitempty"`
+	StationLimit     int            `json:"station_limit,omitempty"`
 }
 
 func NewGame(newGameFile string, gameName string) (*GameState, error) {
-	var newGameSettings NewGameSettings
 	newGameData, err := ioutil.ReadFile(newGameFile)
 	if err != nil {
 		return nil, fmt.Errorf("Could not read new game file at %v: %v", newGameFile, err)
 	}
-	err = json.Unmarshal(newGameData, &newGameSettings)
+	gs, err := newGameFromSettings(newGameData, gameName)
 	if err != nil {
-		return nil, fmt.Errorf("Invalid new game JSON file at %v: %v", newGameFile, err)
+		return nil, fmt.Errorf("Could not set up new game from %v: %v", newGameFile, err)
+	}
+	gs.Journal = &Journal{Setup: json.RawMessage(newGameData), Events: []Event{}}
+	return gs, nil
+}
+
+// NewGameFromSettings starts a game from settings that were not read from a
+// file, such as the ones derived from a campaign.
+func NewGameFromSettings(settings *NewGameSettings, gameName string) (*GameState, error) {
+	newGameData, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	gs, err := newGameFromSettings(newGameData, gameName)
+	if err != nil {
+		return nil, err
+	}
+	gs.Journal = &Journal{Setup: json.RawMessage(newGameData), Events: []Event{}}
+	return gs, nil
+}
+
+func newGameFromSettings(newGameData []byte, gameName string) (*GameState, error) {
+	var newGameSettings NewGameSettings
+	err := json.Unmarshal(newGameData, &newGameSettings)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid new game JSON: %v", err)
 	}
 	cities := Cities(newGameSettings.Cities)
 	players := newGameSettings.Players
@@ -61,6 +103,14 @@ func NewGame(newGameFile string, gameName string) (*GameState, error) {
 		return nil, fmt.Errorf("Duplicate cities detected, check the start information (%v): %+v", len(excludeFromCityDeck), excludeFromCityDeck)
 	}
 
+	if err := newGameSettings.Validate(); err != nil {
+		return nil, err
+	}
+	diseases := newGameSettings.Diseases
+	if len(diseases) == 0 {
+		diseases = DefaultDiseases()
+	}
+	placeStartStation(cities, players)
 	cityDeck, err := cities.GenerateCityDeck(newGameSettings.EpidemicsPerGame, newGameSettings.FundedEvents, excludeFromCityDeck)
 	if err != nil {
 		return nil, err
@@ -78,67 +128,93 @@ func NewGame(newGameFile string, gameName string) (*GameState, error) {
 
 	infectionDeck := NewInfectionDeck(cities.CityNames())
 	return &GameState{
+		board:         NewBoard(cities),
+		SchemaVersion: SchemaVersion,
 		Cities:        &cities,
-		DiseaseData:   []DiseaseData{Yellow, Red, Black, Blue, Faded},
+		DiseaseData:   diseases,
+		CureModifiers: newGameSettings.CureModifiers,
+		HandLimit:     newGameSettings.HandLimit,
+		StationLimit:  newGameSettings.StationLimit,
 		CityDeck:      &cityDeck,
 		InfectionDeck: infectionDeck,
 		InfectionRate: 2,
 		Outbreaks:     0,
+		DiseaseSupply: NewDiseaseSupply(diseases, &cities),
 		GameName:      gameName,
 		GameTurns:     InitGameTurns(players...),
 		IsStarted:     false,
 	}, nil
 }
 
+// LoadGame reads either an event-sourced save or an older snapshot of the
+// game state.
 func LoadGame(gameFile string) (*GameState, error) {
-	var gameState GameState
 	data, err := ioutil.ReadFile(gameFile)
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(data, &gameState)
+	data, _, err = Migrate(data)
+	if err != nil {
+		return nil, fmt.Errorf("Could not migrate %v: %v", gameFile, err)
+	}
+	if isSavedGame(data) {
+		return loadSavedGame(data)
+	}
+	gs, err := loadSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+	gs.Journal = &Journal{Snapshot: json.RawMessage(data), Events: []Event{}}
+	return gs, nil
+}
+
+func loadSnapshot(data []byte) (*GameState, error) {
+	var gameState GameState
+	err := json.Unmarshal(data, &gameState)
 	if err != nil {
 		return nil, err
 	}
+	if gameState.Cities != nil {
+		gameState.board = NewBoard(*gameState.Cities)
+	}
 	return &gameState, nil
 }
 
+// Clone copies the game without its journal, so that it can be played
+// forward without touching the game or its saves.
+func (gs *GameState) Clone() (*GameState, error) {
+	data, err := json.Marshal(gs)
+	if err != nil {
+		return nil, fmt.Errorf("Could not copy %v: %v", gs.GameName, err)
+	}
+	return loadSnapshot(data)
+}
+
 func (gs GameState) ProbabilityOfCuring(player *Player, dt DiseaseType) float64 {
 	// (diseaseColor choose requiredToCure)*(notDiseaseColor choose totalLessRequired)/(allCards choose totalExpectedDraws)
 	remainingCards := gs.CityDeck.RemainingCardsWith(dt, gs.Cities)
-	// TODO: make disease curability more programatic
-	totalRequired := 5
-	//if dt == Red.Type || dt == Black.Type {
-	//	totalRequired = 4
-	//}
+	totalRequired, err := gs.CardsToCure(player, dt)
+	if err != nil {
+		return 0.0
+	}
 	for _, card := range player.Cards {
 		if !card.IsCity() {
 			continue
 		}
-		city, err := gs.Cities.GetCity(card.CityName)
+		city, err := gs.GetCity(card.CityName)
 		if err != nil {
 			panic("City card with no corresponding city: " + card.CityName)
 		}
-		if city.Disease == dt {
-			totalRequired--
-		}
-	}
-	if player.Character != nil {
-		if player.Character.Type == Scientist {
+		if city.Disease == dt && city.PanicLevel.CityCardsUsable() {
 			totalRequired--
-		} else if player.Character.Type == Colonel {
-			totalRequired += 2
-		} else if player.Character.Type == Soldier {
-			return 0.0
 		}
 	}
-
 	allRemaining := gs.CityDeck.RemainingCards()
 	drawsRemaining := 2 * (gs.GameTurns.RemainingTurnsFor(allRemaining, player.HumanName) - 1) // you don't get to use your last draw
 	return combinations.AtLeastNDraws(allRemaining, drawsRemaining, totalRequired, remainingCards)
 }
 
-func (gs GameState) StartDrawCard(cn CardName) error {
+func (gs *GameState) StartDrawCard(cn CardName) error {
 	curTurn, err := gs.GameTurns.CurrentTurn()
 	if err != nil {
 		return err
@@ -151,10 +227,11 @@ func (gs GameState) StartDrawCard(cn CardName) error {
 	//curTurn.Player.Cards = append(curTurn.Player.Cards, card)
 	curTurn.Player.StartCards = append(curTurn.Player.StartCards, cn)
 	curTurn.Player.Cards = append(curTurn.Player.Cards, card)
+	gs.record(Event{Type: StartDrawEvent, Card: cn})
 	return nil
 }
 
-func (gs GameState) DrawCard(cn CardName) error {
+func (gs *GameState) DrawCard(cn CardName) error {
 	curTurn, err := gs.GameTurns.CurrentTurn()
 	if err != nil {
 		return err
@@ -162,20 +239,54 @@ func (gs GameState) DrawCard(cn CardName) error {
 	if gs.IsStarted && len(curTurn.DrawnCards) == CityCardsPerTurn {
 		return fmt.Errorf("%v has already drawn %v cards this turn.", curTurn.Player.HumanName, CityCardsPerTurn)
 	}
+	if err := gs.requirePhase("draw city cards", ActionsPhase, DrawPhase); err != nil {
+		return err
+	}
 	card, err := gs.CityDeck.DrawCard(cn)
 	if err != nil {
 		return err
 	}
 	curTurn.DrawnCards = append(curTurn.DrawnCards, card)
 	curTurn.Player.Cards = append(curTurn.Player.Cards, card)
+	gs.cardDrawn(curTurn)
+	gs.record(Event{Type: CityDrawEvent, Card: cn})
 	return nil
 }
 
-func (gs GameState) NextTurn() (*Turn, error) {
-	return gs.GameTurns.NextTurn()
+// NextTurn moves on to the next player once the turn is done, as long as
+// nobody is over their hand limit.
+func (gs *GameState) NextTurn() (*Turn, error) {
+	if err := gs.requirePhase("end the turn", DonePhase); err != nil {
+		return nil, err
+	}
+	if pending := gs.PendingDiscards(); len(pending) > 0 {
+		return nil, fmt.Errorf("%v must discard or play %v cards first", pending[0].Player.HumanName, pending[0].Excess())
+	}
+	return gs.advanceTurn()
 }
 
-func (gs GameState) ExchangeCard(from, to *Player, name CardName) error {
+// advanceTurn moves on to the next player without checking hand limits,
+// which older games were played without.
+func (gs *GameState) advanceTurn() (*Turn, error) {
+	turn, err := gs.GameTurns.NextTurn()
+	if err != nil {
+		return nil, err
+	}
+	gs.record(Event{Type: NextTurnEvent})
+	return turn, nil
+}
+
+// ExchangeCard shares knowledge: one player gives a card to another in the
+// same city.
+func (gs *GameState) ExchangeCard(from, to *Player, name CardName) error {
+	if err := gs.requireAction("share knowledge"); err != nil {
+		return err
+	}
+	if gs.checksRules() {
+		if err := gs.checkShare(from, to, name); err != nil {
+			return err
+		}
+	}
 	var senderNewCards []*CityCard
 	var toGive *CityCard
 	for _, card := range from.Cards {
@@ -190,112 +301,281 @@ func (gs GameState) ExchangeCard(from, to *Player, name CardName) error {
 	}
 	from.Cards = senderNewCards
 	to.Cards = append(to.Cards, toGive)
+	gs.useAction()
+	gs.record(Event{Type: GiveCardEvent, Player: from.HumanName, To: to.HumanName, Card: name})
+	return nil
+}
+
+func (gs *GameState) Discard(player *Player, name CardName) error {
+	err := player.Discard(name)
+	if err != nil {
+		return err
+	}
+	gs.record(Event{Type: DiscardEvent, Player: player.HumanName, Card: name})
+	return nil
+}
+
+// MovePlayer moves a pawn to the city with whichever legal move the
+// current player can make there.
+func (gs *GameState) MovePlayer(player *Player, cn CityName) error {
+	return gs.MovePawn(player, cn, "", "")
+}
+
+// SetLocation puts a pawn in a city without taking an action, to set up or
+// correct where the players are.
+func (gs *GameState) SetLocation(player *Player, cn CityName) error {
+	if err := gs.placePlayer(player, cn); err != nil {
+		return err
+	}
+	gs.record(Event{Type: LocationEvent, Player: player.HumanName, City: cn})
 	return nil
 }
 
-func (gs *GameState) Infect(cn CityName) (string, error) {
+func (gs *GameState) placePlayer(player *Player, cn CityName) error {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if !gs.IgnoreRules && !city.PanicLevel.CanMoveInto() {
+		return fmt.Errorf("%v has fallen, players can no longer move there", cn)
+	}
+	err = player.SetLocation(cn)
+	if err != nil {
+		return err
+	}
+	gs.enterCity(player, cn)
+	return nil
+}
+
+func (gs *GameState) SetInfectionRate(rate int) {
+	gs.InfectionRate = rate
+	gs.record(Event{Type: InfectionRateEvent, Value: rate})
+}
+
+// SetInfections sets the number of cubes of a disease on a city. An empty
+// disease means the city's own disease.
+func (gs *GameState) SetInfections(cn CityName, dt DiseaseType, infections int) error {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if dt == "" {
+		dt = city.Disease
+	}
+	before := city.CubesOf(dt)
+	city.SetInfections(dt, infections)
+	gs.placeCubes(city, dt, before)
+	gs.checkEradication(dt)
+	gs.record(Event{Type: SetInfectionsEvent, City: cn, Disease: dt, Value: infections})
+	return nil
+}
+
+// TreatInfections removes cubes of a disease from a city without taking an
+// action, to correct the board. An empty disease means the city's own
+// disease.
+func (gs *GameState) TreatInfections(cn CityName, dt DiseaseType, infections int) error {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if dt == "" {
+		dt = city.Disease
+	}
+	if gs.DataForDisease(dt).Untreatable {
+		return fmt.Errorf("%v cannot be treated", dt)
+	}
+	before := city.CubesOf(dt)
+	city.TreatInfections(dt, infections)
+	gs.placeCubes(city, dt, before)
+	gs.checkEradication(dt)
+	gs.record(Event{Type: TreatEvent, City: cn, Disease: dt, Value: infections})
+	return nil
+}
+
+// Infect draws the city from the infection deck and adds a cube to it. The
+// returned result describes the outbreak cascade, and is nil if the city did
+// not outbreak.
+func (gs *GameState) Infect(cn CityName) (*OutbreakResult, error) {
+	if err := gs.requirePhase("infect cities", InfectPhase); err != nil {
+		return nil, err
+	}
+	result, err := gs.infect(cn)
+	if err != nil {
+		return nil, err
+	}
+	gs.infectionDrawn()
+	gs.record(Event{Type: InfectEvent, City: cn})
+	return result, nil
+}
+
+func (gs *GameState) infect(cn CityName) (*OutbreakResult, error) {
 	err := gs.InfectionDeck.Draw(cn)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	city, err := gs.Cities.GetCity(cn)
+	city, err := gs.GetCity(cn)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if city.Quarantined {
-		if !gs.quarantineSpecialistPresent(cn) {
+		if !gs.abilityPresent(cn, HoldsQuarantines) {
 			city.RemoveQuarantine()
 		}
-		return "", nil
+		return nil, nil
+	}
+	if gs.IsEradicated(city.Disease) || gs.medicProtects(cn, city.Disease) {
+		return nil, nil
 	}
 
+	before := city.CubesOf(city.Disease)
 	if city.Infect() {
-		// handle outbreaks
-		gs.Outbreaks += 1
-		outbreakedCities := Set{}
-		outbreakedCities.Add(city.Name)
-		err := gs.HandleOutbreak(city, &outbreakedCities)
-		if err != nil {
-			return "", err
-		}
-		return "Outbreak!!!", nil
+		return gs.startOutbreak(city)
 	}
-	return "", nil
+	gs.placeCubes(city, city.Disease, before)
+	return nil, nil
 }
 
-func (gs *GameState) HandleOutbreak(city *City, outbreakedCities *Set) error {
-	for _, neighbor := range city.Neighbors {
-		cityName, err := GetCityByPrefix(neighbor, gs)
-		if err != nil {
-			return err
-		}
+// startOutbreak handles an outbreak of the city's own disease and
+// everything it chains into, and adds the result to the current turn.
+func (gs *GameState) startOutbreak(city *City) (*OutbreakResult, error) {
+	result := newOutbreakResult(city.Name, city.Disease)
+	outbreakedCities := Set{}
+	err := gs.outbreak(city, &outbreakedCities, result, 0)
+	if err != nil {
+		return nil, err
+	}
+	if curTurn, err := gs.GameTurns.CurrentTurn(); err == nil {
+		curTurn.Outbreaks = append(curTurn.Outbreaks, result)
+	}
+	return result, nil
+}
+
+// outbreak counts an outbreak in the city, raises its panic level and
+// spreads the infection to its neighbors.
+func (gs *GameState) outbreak(city *City, outbreakedCities *Set, result *OutbreakResult, depth int) error {
+	gs.Outbreaks += 1
+	city.RaisePanic()
+	if city.loseStation() {
+		result.StationsLost = append(result.StationsLost, city.Name)
+	}
+	outbreakedCities.Add(city.Name)
+	return gs.HandleOutbreak(city, outbreakedCities, result, depth)
+}
+
+// HandleOutbreak places a cube of the outbreaking disease on every neighbor
+// of an outbreaking city, whatever the neighbor's own disease. A
+// quarantined neighbor does not get a cube, but loses its quarantine unless
+// the quarantine specialist is there.
+func (gs *GameState) HandleOutbreak(city *City, outbreakedCities *Set, result *OutbreakResult, depth int) error {
+	for _, neighborCity := range gs.Board().NeighborCities(city.Name) {
+		cityName := neighborCity.Name
+		step := OutbreakStep{From: city.Name, To: cityName, Depth: depth}
 
 		if outbreakedCities.Contains(cityName) {
+			step.Effect = AlreadyOutbreaked
+			result.add(step)
 			continue
 		}
 
-		city, err := gs.Cities.GetCity(cityName)
-		if err != nil {
-			return err
+		if gs.medicProtects(cityName, result.Disease) {
+			step.Effect = MedicProtected
+			result.add(step)
+			continue
+		}
+
+		if neighborCity.Quarantined {
+			step.Effect = QuarantineHeld
+			if !gs.abilityPresent(cityName, HoldsQuarantines) {
+				neighborCity.RemoveQuarantine()
+				step.Effect = QuarantineConsumed
+			}
+			result.add(step)
+			continue
 		}
 
-		if city.Infect() {
-			gs.Outbreaks += 1
-			outbreakedCities.Add(city.Name)
-			err := gs.HandleOutbreak(city, outbreakedCities)
+		before := neighborCity.CubesOf(result.Disease)
+		if neighborCity.InfectWith(result.Disease) {
+			step.Effect = Chained
+			result.add(step)
+			err := gs.outbreak(neighborCity, outbreakedCities, result, depth+1)
 			if err != nil {
 				return err
 			}
+			continue
 		}
+		gs.placeCubes(neighborCity, result.Disease, before)
+		step.Effect = CubeAdded
+		result.add(step)
 	}
 	return nil
 }
 
-func (gs *GameState) Epidemic(cn CityName) (string, error) {
+// Epidemic pulls the city from the bottom of the infection deck, fills it
+// with cubes and shuffles the infection discard pile back on top. The
+// returned result is nil if the city did not outbreak.
+func (gs *GameState) Epidemic(cn CityName) (*OutbreakResult, error) {
+	if err := gs.requirePhase("draw an epidemic", ActionsPhase, DrawPhase); err != nil {
+		return nil, err
+	}
+	result, err := gs.epidemic(cn)
+	if err != nil {
+		return nil, err
+	}
+	if curTurn, err := gs.GameTurns.CurrentTurn(); err == nil && gs.IsStarted {
+		curTurn.EpidemicsDrawn++
+		gs.cardDrawn(curTurn)
+	}
+	gs.record(Event{Type: EpidemicEvent, City: cn})
+	return result, nil
+}
+
+func (gs *GameState) epidemic(cn CityName) (*OutbreakResult, error) {
 	err := gs.InfectionDeck.PullFromBottom(cn)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	err = gs.CityDeck.DrawEpidemic()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	city, _ := gs.Cities.GetCity(cn)
+	city, _ := gs.GetCity(cn)
 
+	var result *OutbreakResult
 	if city.Quarantined {
-		if !gs.quarantineSpecialistPresent(cn) {
+		if !gs.abilityPresent(cn, HoldsQuarantines) {
 			city.RemoveQuarantine()
 		}
-	} else {
-		if city.Epidemic() {
-			// handle outbreak
-			gs.Outbreaks += 1
-			outbreakedCities := Set{}
-			outbreakedCities.Add(city.Name)
-			err := gs.HandleOutbreak(city, &outbreakedCities)
+	} else if !gs.IsEradicated(city.Disease) && !gs.medicProtects(cn, city.Disease) {
+		before := city.CubesOf(city.Disease)
+		outbreaks := city.Epidemic()
+		gs.placeCubes(city, city.Disease, before)
+		if outbreaks {
+			result, err = gs.startOutbreak(city)
 			if err != nil {
-				return "", err
+				return nil, err
 			}
-			return "Outbreak!!!", nil
 		}
 	}
 	gs.InfectionDeck.ShuffleDrawn()
-	return "", nil
+	return result, nil
 }
 
-func (gs GameState) quarantineSpecialistPresent(cityName CityName) bool {
-	for _, player := range gs.GameTurns.PlayerOrder {
-		if player.Location == cityName &&
-			player.Character != nil && // TODO: actually support characters and remove null check
-			player.Character.Type == QuarantineSpecialist {
-			return true
-		}
+func (gs *GameState) SetPanicLevel(cn CityName, level PanicLevel) error {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return err
 	}
-	return false
+	if level < Nothing || level > Fallen {
+		return fmt.Errorf("Invalid panic level %d", level)
+	}
+	city.PanicLevel = level
+	city.loseStation()
+	gs.record(Event{Type: PanicEvent, City: cn, Value: int(level)})
+	return nil
 }
 
-func (gs GameState) Quarantine(cn CityName) error {
-	city, err := gs.Cities.GetCity(cn)
+func (gs *GameState) Quarantine(cn CityName) error {
+	city, err := gs.GetCity(cn)
 	if err != nil {
 		return err
 	}
@@ -303,11 +583,12 @@ func (gs GameState) Quarantine(cn CityName) error {
 		return fmt.Errorf("%v is already quarantined", cn)
 	}
 	city.Quarantine()
+	gs.record(Event{Type: QuarantineEvent, City: cn})
 	return nil
 }
 
-func (gs GameState) RemoveQuarantine(cn CityName) error {
-	city, err := gs.Cities.GetCity(cn)
+func (gs *GameState) RemoveQuarantine(cn CityName) error {
+	city, err := gs.GetCity(cn)
 	if err != nil {
 		return err
 	}
@@ -315,58 +596,65 @@ func (gs GameState) RemoveQuarantine(cn CityName) error {
 		return fmt.Errorf("%v is not quarantined ", cn)
 	}
 	city.RemoveQuarantine()
+	gs.record(Event{Type: RemoveQuarantineEvent, City: cn})
 	return nil
 }
 
-// ProbabilityOfCity gives the aggregate probability of a city
-// becoming infected. Quarantines make the probabilty of infection
-// zero. This does not take into account the probability of infection
-// due to neighboring city outbreaks.
+// ProbabilityOfCity gives the probability of a city being infected at
+// least once during the coming turn. See InfectionOdds.
 func (gs GameState) ProbabilityOfCity(cn CityName) float64 {
-	city, err := gs.Cities.GetCity(cn)
+	return gs.InfectionOdds(cn).AtLeastOne
+}
+
+// CanOutbreak is true if the city could outbreak with any disease.
+func (gs GameState) CanOutbreak(cn CityName) bool {
+	city, err := gs.GetCity(cn)
 	if err != nil {
-		return 0.0
-	}
-	if city.Quarantined {
-		return 0.0
+		return false
 	}
-	var cityDrawInfectRate float64
-	// Check: does a city with 3 get additionally infected on drawing the city card?
-	// Assume no, and no outbreak, for now.
-	if DataForDisease(city.Disease).InfectOnCityDraw && city.NumInfections < 3 {
-		cityDrawInfectRate = gs.CityDeck.ProbabilityOfDrawing(cn.CardName())
+	if gs.CanOutbreakWith(cn, city.Disease) {
+		return true
 	}
-	// P(epidemic)*P(pull from bottom or from infect drawn) + P(!epidemic)*P(infection deck draw)
-	pEpi := gs.CityDeck.probabilityOfEpidemic()
-	bottom := gs.InfectionDeck.BottomStriation()
-	var pEpiDraw float64
-	if bottom.Contains(cn) {
-		pEpiDraw = 1.0 / float64(bottom.Size())
-	} else if gs.InfectionDeck.Drawn.Contains(cn) {
-		pEpiDraw = float64(gs.InfectionRate) / (1.0 + float64(len(gs.InfectionDeck.Drawn)))
+	for dt := range city.Cubes {
+		if dt != city.Disease && gs.CanOutbreakWith(cn, dt) {
+			return true
+		}
 	}
-
-	pNoEpiDraw := gs.InfectionDeck.ProbabilityOfDrawing(cn, gs.InfectionRate)
-	return cityDrawInfectRate + pEpi*pEpiDraw + (1.0-pEpi)*pNoEpiDraw
+	return false
 }
 
-func (gs GameState) CanOutbreak(cn CityName) bool {
-	city, err := gs.Cities.GetCity(cn)
+// CanOutbreakWith is true if the city could outbreak with the disease. The
+// city's own disease outbreaks from infection cards and epidemics. Any
+// other disease only arrives through outbreaks, so the city must already
+// hold 3 of its cubes next to a city of that colour which can outbreak.
+func (gs GameState) CanOutbreakWith(cn CityName, dt DiseaseType) bool {
+	city, err := gs.GetCity(cn)
 	if err != nil {
 		return false
 	}
-	if city.NumInfections == 0 && !DataForDisease(city.Disease).InfectOnCityDraw {
+	if dt != city.Disease {
+		if city.CubesOf(dt) < 3 || city.Quarantined {
+			return false
+		}
+		for _, neighborCity := range gs.Board().NeighborCities(cn) {
+			if neighborCity.Disease == dt && gs.CanOutbreakWith(neighborCity.Name, dt) {
+				return true
+			}
+		}
+		return false
+	}
+	if city.CubesOf(dt) == 0 && !gs.DataForDisease(city.Disease).InfectOnCityDraw {
 		return false
 	}
 	prob := gs.ProbabilityOfCity(cn)
 	if prob == 0.0 {
 		return false
 	}
-	return city.NumInfections == 3 || gs.InfectionDeck.BottomStriation().Contains(cn)
+	return city.CubesOf(dt) == 3 || gs.InfectionDeck.BottomStriation().Contains(cn)
 }
 
 func (gs *GameState) GetCity(city CityName) (*City, error) {
-	return gs.Cities.GetCity(city)
+	return gs.Board().City(city)
 }
 
 func (gs *GameState) GetDiseaseData(diseaseType DiseaseType) (*DiseaseData, error) {
@@ -386,6 +674,7 @@ func (gs *GameState) SortBySeverity(names []CityName) []CityName {
 
 func (gs *GameState) StartGame() {
 	gs.IsStarted = true
+	gs.record(Event{Type: StartGameEvent})
 }
 
 type bySeverity struct {
@@ -403,12 +692,18 @@ func (b bySeverity) Less(i, j int) bool {
 	nameI := b.names[i]
 	nameJ := b.names[j]
 
-	cityI, _ := b.gs.Cities.GetCity(nameI)
-	cityJ, _ := b.gs.Cities.GetCity(nameJ)
-	if cityI.NumInfections > cityJ.NumInfections {
+	cityI, _ := b.gs.GetCity(nameI)
+	cityJ, _ := b.gs.GetCity(nameJ)
+	if cityI.MaxCubes() > cityJ.MaxCubes() {
+		return true
+	}
+	if cityI.MaxCubes() < cityJ.MaxCubes() {
+		return false
+	}
+	if cityI.TotalCubes() > cityJ.TotalCubes() {
 		return true
 	}
-	if cityI.NumInfections < cityJ.NumInfections {
+	if cityI.TotalCubes() < cityJ.TotalCubes() {
 		return false
 	}
 	cityIProb := b.gs.ProbabilityOfCity(nameI)
@@ -242,7 +242,7 @@ var infectTests = []testExpectation{
 		},
 		infectProbabilities: map[string]float64{
 			"a": 0.2,  // 2 draws out of 10, 1/5 chance
-			"g": 0.29, // 2 draws out of 10  plus one 1/11 from the city deck, because it's faded
+			"g": 0.36, // 2 draws out of 10, or its faded card among the 2 city cards, 1 - 4/5*4/5
 		},
 	},
 	{
@@ -305,7 +305,7 @@ var infectTests = []testExpectation{
 func TestRunInfectTests(t *testing.T) {
 	for _, infectTest := range infectTests {
 		// SETUP
-		gs := GameState{}
+		gs := GameState{DiseaseData: DefaultDiseases()}
 		cities, cityDeck, err := getTestCityDeck()
 		if infectTest.state.lopsided {
 			cities, cityDeck, err = generateLopsidedCityDeck()
@@ -339,22 +339,30 @@ func TestRunInfectTests(t *testing.T) {
 func TestSortByInfect(t *testing.T) {
 	cities := Cities([]*City{
 		{
-			Name:          "a",
-			NumInfections: 2,
+			Name:    "a",
+			Disease: Blue.Type,
+			Cubes:   map[DiseaseType]int{Blue.Type: 2},
 		},
 		{
-			Name:          "b",
-			NumInfections: 3,
+			Name:    "b",
+			Disease: Blue.Type,
+			Cubes:   map[DiseaseType]int{Blue.Type: 3},
 		},
 		{
-			Name:          "c",
-			NumInfections: 1,
+			Name:    "c",
+			Disease: Blue.Type,
+			Cubes:   map[DiseaseType]int{Blue.Type: 1, Red.Type: 1},
+		},
+		{
+			Name:    "d",
+			Disease: Blue.Type,
+			Cubes:   map[DiseaseType]int{Red.Type: 1},
 		},
 	},
 	)
 	gameState := GameState{Cities: &cities}
-	sorted := gameState.SortBySeverity([]CityName{"a", "b", "c"})
-	if len(sorted) != 3 || sorted[0] != "b" || sorted[1] != "a" || sorted[2] != "c" {
+	sorted := gameState.SortBySeverity([]CityName{"a", "b", "c", "d"})
+	if len(sorted) != 4 || sorted[0] != "b" || sorted[1] != "a" || sorted[2] != "c" || sorted[3] != "d" {
 		t.Fatalf("Incorrect order: %+v", sorted)
 	}
 }
@@ -0,0 +1,82 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PendingDiscard is a player holding more cards than their hand limit, who
+// has to discard or play cards before the turn can end.
+type PendingDiscard struct {
+	Player *Player
+	Limit  int
+}
+
+// Excess is the number of cards the player has to get rid of.
+func (p PendingDiscard) Excess() int {
+	return len(p.Player.Cards) - p.Limit
+}
+
+func (p PendingDiscard) String() string {
+	cards := []string{}
+	for _, card := range p.Player.Cards {
+		cards = append(cards, string(card.Name()))
+	}
+	return fmt.Sprintf("%v holds %v cards, over the hand limit of %v. Discard or play %v of: %v",
+		p.Player.HumanName, len(p.Player.Cards), p.Limit, p.Excess(), strings.Join(cards, ", "))
+}
+
+// HandLimitOf is the number of cards the player may hold: the game's hand
+// limit, adjusted by the character's abilities.
+func (gs *GameState) HandLimitOf(player *Player) int {
+	limit := HandLimit
+	if gs.HandLimit > 0 {
+		limit = gs.HandLimit
+	}
+	return limit + player.Abilities().HandLimit() - HandLimit
+}
+
+// PendingDiscards lists the players over their hand limit, in turn order.
+func (gs *GameState) PendingDiscards() []PendingDiscard {
+	pending := []PendingDiscard{}
+	for _, player := range gs.GameTurns.PlayerOrder {
+		if limit := gs.HandLimitOf(player); len(player.Cards) > limit {
+			pending = append(pending, PendingDiscard{player, limit})
+		}
+	}
+	return pending
+}
+
+// CardHolder returns the player holding the card, or nil if nobody does.
+func (gs *GameState) CardHolder(name CardName) *Player {
+	for _, player := range gs.GameTurns.PlayerOrder {
+		for _, card := range player.Cards {
+			if card.Name() == name {
+				return player
+			}
+		}
+	}
+	return nil
+}
+
+// PlayEventCard plays a funded event from the player's hand, which discards
+// it. The effect of the event is left to the players.
+func (gs *GameState) PlayEventCard(player *Player, name CardName) error {
+	var toPlay *CityCard
+	for _, card := range player.Cards {
+		if card.Name() == name {
+			toPlay = card
+		}
+	}
+	if toPlay == nil {
+		return fmt.Errorf("%v does not seem to have %v", player.HumanName, name)
+	}
+	if !toPlay.IsFundedEvent() {
+		return fmt.Errorf("%v is not an event card", name)
+	}
+	if err := player.Discard(name); err != nil {
+		return err
+	}
+	gs.record(Event{Type: PlayEventCardEvent, Player: player.HumanName, Card: name})
+	return nil
+}
@@ -0,0 +1,89 @@
+package pandemic
+
+import "testing"
+
+func TestHandLimitBlocksNextTurn(t *testing.T) {
+	gs := newTestGame(t)
+	player := gs.GameTurns.PlayerOrder[0]
+	other := gs.GameTurns.PlayerOrder[1]
+	cards := []CardName{"atlanta", "chicago", "paris", "london", "madrid", "essen", "milan", "airlift"}
+	for _, cn := range cards {
+		if err := gs.DrawCard(cn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pending := gs.PendingDiscards()
+	if len(pending) != 1 || pending[0].Player != player || pending[0].Excess() != 1 {
+		t.Fatalf("Expected %v to have to discard 1 card, got %v", player.HumanName, pending)
+	}
+	if _, err := gs.NextTurn(); err == nil {
+		t.Fatal("The turn should not end while a player is over the hand limit")
+	}
+	if err := gs.PlayEventCard(player, "paris"); err == nil {
+		t.Fatal("City cards cannot be played as events")
+	}
+	if err := gs.PlayEventCard(player, "airlift"); err != nil {
+		t.Fatal(err)
+	}
+	if len(gs.PendingDiscards()) != 0 {
+		t.Fatal("Playing the event should resolve the pending discard")
+	}
+
+	for i := 0; i < 7; i++ {
+		if err := gs.ExchangeCard(player, other, cards[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gs.DrawCard("newyork"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.ExchangeCard(player, other, "newyork"); err != nil {
+		t.Fatal(err)
+	}
+	if pending := gs.PendingDiscards(); len(pending) != 1 || pending[0].Player != other {
+		t.Fatalf("Receiving a card should put %v over the hand limit, got %v", other.HumanName, pending)
+	}
+	if err := gs.Discard(gs.CardHolder("madrid"), "madrid"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.NextTurn(); err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt, err := gs.Journal.rebuild("test", gs.Journal.Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marshalState(t, rebuilt) != marshalState(t, gs) {
+		t.Fatal("Playing an event card should replay")
+	}
+}
+
+func TestHandLimitOf(t *testing.T) {
+	gs := newTestGame(t)
+	player := gs.GameTurns.PlayerOrder[0]
+	if gs.HandLimitOf(player) != HandLimit {
+		t.Fatalf("Expected the base hand limit, got %v", gs.HandLimitOf(player))
+	}
+	gs.HandLimit = 6
+	player.Character.Type = Archivist
+	if gs.HandLimitOf(player) != 7 {
+		t.Fatalf("Expected the Archivist to hold one more card than the game's limit, got %v", gs.HandLimitOf(player))
+	}
+}
+
+func TestOldGamesReplayTurnsOverTheHandLimit(t *testing.T) {
+	gs := newTestGame(t)
+	events := []Event{}
+	for _, cn := range []CardName{"atlanta", "chicago", "paris", "london", "madrid", "essen", "milan", "newyork"} {
+		events = append(events, Event{Type: CityDrawEvent, Card: cn})
+	}
+	events = append(events, Event{Type: NextTurnEvent})
+	rebuilt, err := gs.Journal.rebuild("test", events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := rebuilt.GameTurns.CurrentTurn(); cur.Player != rebuilt.GameTurns.PlayerOrder[1] {
+		t.Fatal("Saved turns should replay even if a player was over the hand limit")
+	}
+}
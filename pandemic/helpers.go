@@ -2,6 +2,7 @@ package pandemic
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -14,14 +15,51 @@ func GetCardByPrefix(entry string, gs *GameState) (CardName, error) {
 }
 
 func GetCityByPrefix(entry string, gs *GameState) (CityName, error) {
-	card, err := gs.CityDeck.GetCardByPrefix(entry)
+	city, err := gs.Board().CityByPrefix(entry)
 	if err != nil {
 		return CityName(""), err
 	}
-	if !card.IsCity() {
-		return CityName(""), fmt.Errorf("%v is not a city", card.Name())
+	return city.Name, nil
+}
+
+func GetDiseaseByPrefix(entry string, gs *GameState) (DiseaseType, error) {
+	var ret DiseaseType
+	for _, data := range gs.DiseaseData {
+		if strings.HasPrefix(strings.ToLower(string(data.Type)), strings.ToLower(entry)) {
+			if ret != "" {
+				return "", fmt.Errorf("%v is an ambiguous disease", entry)
+			}
+			ret = data.Type
+		}
+	}
+	if ret == "" {
+		return "", fmt.Errorf("%v is not a disease", entry)
 	}
-	return card.CityName, nil
+	return ret, nil
+}
+
+// GetPanicLevelByPrefix accepts either the number of a panic level or a
+// prefix of its name.
+func GetPanicLevelByPrefix(entry string) (PanicLevel, error) {
+	if level, err := strconv.Atoi(entry); err == nil {
+		if level < int(Nothing) || level > int(Fallen) {
+			return PanicLevel(-1), fmt.Errorf("Panic level must be between %d and %d", Nothing, Fallen)
+		}
+		return PanicLevel(level), nil
+	}
+	ret := PanicLevel(-1)
+	for level := Nothing; level <= Fallen; level++ {
+		if strings.HasPrefix(strings.ToLower(level.String()), strings.ToLower(entry)) {
+			if ret != PanicLevel(-1) {
+				return PanicLevel(-1), fmt.Errorf("%v is an ambiguous panic level", entry)
+			}
+			ret = level
+		}
+	}
+	if ret == PanicLevel(-1) {
+		return ret, fmt.Errorf("%v is not a panic level", entry)
+	}
+	return ret, nil
 }
 
 func GetPlayerByPrefix(entry string, gs *GameState) (*Player, error) {
@@ -41,6 +79,9 @@ func GetPlayerByPrefix(entry string, gs *GameState) (*Player, error) {
 func GetPlayerByCharacter(entry string, gs *GameState) (*Player, error) {
 	var ret *Player
 	for _, player := range gs.GameTurns.PlayerOrder {
+		if player.Character == nil {
+			continue
+		}
 		if strings.HasPrefix(strings.ToLower(string(player.Character.Type)), strings.ToLower(entry)) {
 			if ret != nil {
 				return nil, fmt.Errorf("%v is an ambiguous name", entry)
@@ -51,3 +92,12 @@ func GetPlayerByCharacter(entry string, gs *GameState) (*Player, error) {
 	}
 	return ret, nil
 }
+
+func GetTurnPhaseByPrefix(entry string) (TurnPhase, error) {
+	for _, phase := range turnPhases {
+		if entry != "" && strings.HasPrefix(string(phase), strings.ToLower(entry)) {
+			return phase, nil
+		}
+	}
+	return "", fmt.Errorf("%v is not a turn phase", entry)
+}
@@ -36,7 +36,7 @@ func (d *InfectionDeck) Draw(cityName CityName) error {
 		return fmt.Errorf("Card %v is not present in the active striation - how the fuck did you draw this card?", cityName)
 	}
 	d.Drawn.Add(cityName)
-	for d.Striations[0].Size() == 0 {
+	for len(d.Striations) > 0 && d.Striations[0].Size() == 0 {
 		d.Striations = d.Striations[1:]
 	}
 	return nil
@@ -68,6 +68,10 @@ func (d *InfectionDeck) PullFromBottom(card CityName) error {
 		return fmt.Errorf("Card %v should not be present in the bottom striation", card)
 	}
 	d.Drawn.Add(card)
+	// the next epidemic pulls from the striation above an emptied one
+	if bottomStriation.Size() == 0 && len(d.Striations) > 1 {
+		d.Striations = d.Striations[:len(d.Striations)-1]
+	}
 	return nil
 }
 
@@ -96,45 +100,11 @@ func (d *InfectionDeck) DrawnCount() int {
 	return d.Drawn.Size()
 }
 
+// ProbabilityOfDrawing is the chance that the city is among the next
+// cards drawn. A striation is drawn entirely before the next one, so a card
+// is certain to be drawn once every striation above it is exhausted.
 func (d *InfectionDeck) ProbabilityOfDrawing(city CityName, infectionRate int) float64 {
-	// Has the city already been drawn?
-	if d.Drawn.Contains(city) {
-		return 0.0
-	}
-
-	// Clone myself so we can recurse into the future. <- coolest code comment I've ever left.
-	dCopy := *d
-
-	// Probability of ANY of the infection cards being the City is equal to 1 minus the probabilty
-	// that *none* of the cards is the city card
-	//
-	// P(C) ~= 1 - P(!C)^numCardsRemaining
-	// Assuming 10 cards in the striation and infection rate = 4
-	// P(C) = 1 - (9/10)*(8/9)*(7/8)*(6/7) = 1 - 6/10 = 40%
-	probability := 1.0
-	curStriationSize := dCopy.Striations[0].Size()
-	for draw := 0; draw < infectionRate; draw++ {
-		// if we've run out of cards in this striation, pop and
-		// start using the next striation down.
-		for curStriationSize == 0 {
-			dCopy.Striations = dCopy.Striations[1:]
-			dCopy.assertStriationCount()
-			curStriationSize = dCopy.Striations[0].Size()
-		}
-
-		// calculate the probability of drawing the given card
-		// based on it's presence in the current striation. If
-		// it is not present, the chance of not drawing the
-		// target card is 100%.
-		if dCopy.Striations[0].Contains(city) {
-			probability *= float64(curStriationSize-1) / float64(curStriationSize)
-		}
-		// Reduce the count of cards we know will remain in this
-		// striation after drawing a card
-		curStriationSize = curStriationSize - 1
-	}
-
-	return 1 - probability
+	return d.shape(city).drawChance(infectionRate)
 }
 
 func (deck *InfectionDeck) DrawnContains(city CityName) bool {
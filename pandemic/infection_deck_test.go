@@ -52,3 +52,19 @@ func TestProbabilityOfStriations(t *testing.T) {
 	checkProbability(t, deck, "Washington", 1, 0.0)
 	checkProbability(t, deck, "Washington", 2, 0.25)
 }
+
+func TestPullFromEmptiedBottomStriation(t *testing.T) {
+	deck := testInfectionDeck()
+	deck.Draw("SanFrancisco")
+	deck.Draw("NewYork")
+	deck.Draw("Montreal")
+	deck.Draw("Miami")
+	deck.ShuffleDrawn()
+	if err := deck.PullFromBottom("Washington"); err != nil {
+		t.Fatal(err)
+	}
+	deck.ShuffleDrawn()
+	if err := deck.PullFromBottom("Miami"); err != nil {
+		t.Fatalf("The next epidemic should pull from the striation above: %v", err)
+	}
+}
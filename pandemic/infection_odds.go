@@ -0,0 +1,265 @@
+package pandemic
+
+// InfectionRateTrack is the infection rate once the given number of
+// epidemics have been drawn.
+var InfectionRateTrack = []int{2, 2, 2, 3, 3, 4, 4}
+
+// InfectionOdds are the chances of what happens to a city during the coming
+// turn: being infected at least once, at least twice, and outbreaking. An
+// epidemic counts as one infection.
+type InfectionOdds struct {
+	AtLeastOne float64
+	AtLeastTwo float64
+	Outbreak   float64
+}
+
+// DrawOutlook describes the two city cards of the coming turn as seen by a
+// city, indexed by the number of epidemics among them: the chance of
+// drawing that many, the infection rate afterwards, and the chance that the
+// city's own card is drawn, which infects the cities of diseases that
+// infect on city draw.
+type DrawOutlook struct {
+	Epidemics [3]float64
+	Rates     [3]int
+	CityCard  [3]float64
+}
+
+// deckShape is the infection deck as far as one card is concerned. Cards
+// in a striation are in no particular order, so only the sizes of the
+// striations, from the top, and of the drawn pile matter, along with where
+// the card is: in a striation, in the drawn pile (-1), or nowhere (-2).
+type deckShape struct {
+	striations []int
+	drawn      int
+	card       int
+}
+
+func (d *InfectionDeck) shape(cn CityName) deckShape {
+	shape := deckShape{drawn: d.Drawn.Size(), card: -2}
+	if d.Drawn.Contains(cn) {
+		shape.card = -1
+	}
+	for _, striation := range d.Striations {
+		if striation.Size() == 0 {
+			continue
+		}
+		if striation.Contains(cn) {
+			shape.card = len(shape.striations)
+		}
+		shape.striations = append(shape.striations, striation.Size())
+	}
+	return shape
+}
+
+// drawChance is the chance that the card is among the next cards drawn
+// from the top of the deck.
+func (s deckShape) drawChance(draws int) float64 {
+	if s.card < 0 {
+		return 0.0
+	}
+	for _, size := range s.striations[:s.card] {
+		draws -= size
+	}
+	size := s.striations[s.card]
+	if draws <= 0 {
+		return 0.0
+	}
+	if draws >= size {
+		return 1.0
+	}
+	return float64(draws) / float64(size)
+}
+
+// epidemic pulls the bottom card of the deck and shuffles the drawn pile,
+// with the pulled card, back on top. It returns the deck for when the
+// pulled card is the city's, the chance of that, and the deck otherwise.
+func (s deckShape) epidemic() (deckShape, float64, deckShape) {
+	bottom := len(s.striations) - 1
+	if bottom < 0 {
+		return s.intensify(), 0.0, s.intensify()
+	}
+	pulled := deckShape{striations: append([]int{}, s.striations...), drawn: s.drawn + 1, card: s.card}
+	pulled.striations[bottom]--
+	if pulled.striations[bottom] == 0 {
+		pulled.striations = pulled.striations[:bottom]
+	}
+	if s.card != bottom {
+		return pulled.intensify(), 0.0, pulled.intensify()
+	}
+	hit := pulled
+	hit.card = -1
+	return hit.intensify(), 1.0 / float64(s.striations[bottom]), pulled.intensify()
+}
+
+// intensify puts the drawn pile on top of the deck as a new striation.
+func (s deckShape) intensify() deckShape {
+	if s.drawn == 0 {
+		return s
+	}
+	shuffled := deckShape{striations: append([]int{s.drawn}, s.striations...), card: s.card}
+	if s.card >= -1 {
+		shuffled.card++
+	}
+	return shuffled
+}
+
+// infectionPath is one way the turn can go for the city so far.
+type infectionPath struct {
+	p          float64
+	deck       deckShape
+	infections int
+	cubes      int
+	outbreak   bool
+}
+
+func (path infectionPath) with(p float64) infectionPath {
+	path.p *= p
+	return path
+}
+
+// infect places a cube on the city, or 3 for an epidemic.
+func (path infectionPath) infect(epidemic bool) infectionPath {
+	path.infections++
+	if epidemic {
+		path.outbreak = path.outbreak || path.cubes > 0
+		path.cubes = 3
+	} else if path.cubes == 3 {
+		path.outbreak = true
+	} else {
+		path.cubes++
+	}
+	return path
+}
+
+func (odds *InfectionOdds) add(path infectionPath) {
+	if path.infections >= 1 {
+		odds.AtLeastOne += path.p
+	}
+	if path.infections >= 2 {
+		odds.AtLeastTwo += path.p
+	}
+	if path.outbreak {
+		odds.Outbreak += path.p
+	}
+}
+
+// Odds works out exactly what can happen to a city holding the given cubes
+// of its disease over the coming turn. It follows every outcome of the two
+// city cards: the city's own card being drawn, and each epidemic pulling
+// the bottom card and shuffling the drawn pile back on top. Then it draws
+// the infection rate from the top of the deck, striation by striation.
+func (d *InfectionDeck) Odds(cn CityName, cubes int, outlook DrawOutlook) InfectionOdds {
+	var odds InfectionOdds
+	start := infectionPath{p: 1.0, deck: d.shape(cn), cubes: cubes}
+	for epidemics, p := range outlook.Epidemics {
+		if p <= 0.0 {
+			continue
+		}
+		paths := []infectionPath{start.with(p)}
+		if card := outlook.CityCard[epidemics]; card > 0.0 {
+			paths = []infectionPath{paths[0].with(1.0 - card), paths[0].with(card).infect(false)}
+		}
+		for i := 0; i < epidemics; i++ {
+			next := []infectionPath{}
+			for _, path := range paths {
+				hit, pHit, miss := path.deck.epidemic()
+				if pHit > 0.0 {
+					pulled := path.with(pHit).infect(true)
+					pulled.deck = hit
+					next = append(next, pulled)
+				}
+				if pHit < 1.0 {
+					path = path.with(1.0 - pHit)
+					path.deck = miss
+					next = append(next, path)
+				}
+			}
+			paths = next
+		}
+		for _, path := range paths {
+			drawn := path.deck.drawChance(outlook.Rates[epidemics])
+			odds.add(path.with(1.0 - drawn))
+			if drawn > 0.0 {
+				odds.add(path.with(drawn).infect(false))
+			}
+		}
+	}
+	return odds
+}
+
+// epidemicOdds gives the chances of drawing no, one and two epidemics with
+// the next two city cards.
+func (c CityDeck) epidemicOdds() [3]float64 {
+	analysis := c.EpidemicAnalysis()
+	first := clamp(analysis.FirstCardProbability)
+	afterEpi := clamp(analysis.SecondCardEpiAfterFirstEpi)
+	afterCity := clamp(analysis.SecondCardEpiAfterCity)
+	return [3]float64{
+		(1.0 - first) * (1.0 - afterCity),
+		first*(1.0-afterEpi) + (1.0-first)*afterCity,
+		first * afterEpi,
+	}
+}
+
+// cardChance is the chance that the card is among the next cards drawn
+// that are not epidemics.
+func (c CityDeck) cardChance(cn CardName, draws int) float64 {
+	inDeck := false
+	for _, card := range c.All {
+		inDeck = inDeck || card.Name() == cn
+	}
+	for _, card := range c.Drawn {
+		inDeck = inDeck && card.Name() != cn
+	}
+	left := c.Total() - len(c.Drawn) - (c.NumEpidemics() - c.EpidemicsDrawn())
+	if !inDeck || left <= 0 {
+		return 0.0
+	}
+	return clamp(float64(draws) / float64(left))
+}
+
+func clamp(p float64) float64 {
+	if p < 0.0 {
+		return 0.0
+	}
+	if p > 1.0 {
+		return 1.0
+	}
+	return p
+}
+
+// InfectionRateAfter is the infection rate once the given number of
+// epidemics more have been drawn. It follows the InfectionRateTrack, unless
+// the rate is already higher.
+func (gs GameState) InfectionRateAfter(epidemics int) int {
+	rate := gs.InfectionRate
+	if track := gs.CityDeck.EpidemicsDrawn() + epidemics; epidemics > 0 && track < len(InfectionRateTrack) && InfectionRateTrack[track] > rate {
+		rate = InfectionRateTrack[track]
+	}
+	return rate
+}
+
+// drawOutlook looks at the city deck for the city.
+func (gs GameState) drawOutlook(city *City) DrawOutlook {
+	outlook := DrawOutlook{Epidemics: gs.CityDeck.epidemicOdds()}
+	for epidemics := range outlook.Rates {
+		outlook.Rates[epidemics] = gs.InfectionRateAfter(epidemics)
+		if gs.DataForDisease(city.Disease).InfectOnCityDraw {
+			outlook.CityCard[epidemics] = gs.CityDeck.cardChance(city.Name.CardName(), 2-epidemics)
+		}
+	}
+	return outlook
+}
+
+// InfectionOdds gives the chances of the city being infected with its own
+// disease during the coming turn, the only colour infection cards place.
+// Quarantined cities, eradicated diseases and cured diseases where the
+// Medic is are not infected. Outbreaks of neighboring cities are not taken
+// into account.
+func (gs GameState) InfectionOdds(cn CityName) InfectionOdds {
+	city, err := gs.GetCity(cn)
+	if err != nil || city.Quarantined || gs.IsEradicated(city.Disease) || gs.medicProtects(cn, city.Disease) {
+		return InfectionOdds{}
+	}
+	return gs.InfectionDeck.Odds(cn, city.CubesOf(city.Disease), gs.drawOutlook(city))
+}
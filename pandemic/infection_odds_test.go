@@ -0,0 +1,189 @@
+package pandemic
+
+import (
+	"math"
+	"testing"
+)
+
+func permutations(cards []CityName) [][]CityName {
+	if len(cards) <= 1 {
+		return [][]CityName{append([]CityName{}, cards...)}
+	}
+	ret := [][]CityName{}
+	for i, card := range cards {
+		rest := append(append([]CityName{}, cards[:i]...), cards[i+1:]...)
+		for _, perm := range permutations(rest) {
+			ret = append(ret, append([]CityName{card}, perm...))
+		}
+	}
+	return ret
+}
+
+type bruteTurn struct {
+	infections int
+	cubes      int
+	outbreak   bool
+}
+
+func (t bruteTurn) infect(epidemic bool) bruteTurn {
+	city := &City{Disease: Blue.Type}
+	city.SetInfections(Blue.Type, t.cubes)
+	var outbreak bool
+	if epidemic {
+		outbreak = city.Epidemic()
+	} else {
+		outbreak = city.Infect()
+	}
+	return bruteTurn{t.infections + 1, city.CubesOf(Blue.Type), t.outbreak || outbreak}
+}
+
+// bruteForceOdds plays the coming turn with every ordering of the infection
+// deck that its striations allow and every shuffle of the drawn pile, all
+// equally likely.
+func bruteForceOdds(d *InfectionDeck, cn CityName, cubes int, outlook DrawOutlook) InfectionOdds {
+	var odds InfectionOdds
+	var play func(deck, drawn []CityName, epidemics, rate int, turn bruteTurn, weight float64)
+	play = func(deck, drawn []CityName, epidemics, rate int, turn bruteTurn, weight float64) {
+		if epidemics == 0 {
+			for i := 0; i < rate && i < len(deck); i++ {
+				if deck[i] == cn {
+					turn = turn.infect(false)
+				}
+			}
+			if turn.infections >= 1 {
+				odds.AtLeastOne += weight
+			}
+			if turn.infections >= 2 {
+				odds.AtLeastTwo += weight
+			}
+			if turn.outbreak {
+				odds.Outbreak += weight
+			}
+			return
+		}
+		pulled := deck[len(deck)-1]
+		if pulled == cn {
+			turn = turn.infect(true)
+		}
+		shuffles := permutations(append(append([]CityName{}, drawn...), pulled))
+		for _, shuffled := range shuffles {
+			play(append(shuffled, deck[:len(deck)-1]...), nil, epidemics-1, rate, turn, weight/float64(len(shuffles)))
+		}
+	}
+
+	orderings := [][]CityName{{}}
+	for s := range d.Striations {
+		next := [][]CityName{}
+		for _, top := range orderings {
+			for _, perm := range permutations(d.CitiesInStriation(s)) {
+				next = append(next, append(append([]CityName{}, top...), perm...))
+			}
+		}
+		orderings = next
+	}
+	for epidemics, p := range outlook.Epidemics {
+		card := outlook.CityCard[epidemics]
+		for i, q := range []float64{1.0 - card, card} {
+			turn := bruteTurn{cubes: cubes}
+			if i == 1 {
+				turn = turn.infect(false)
+			}
+			for _, deck := range orderings {
+				play(deck, d.CitiesInDrawn(), epidemics, outlook.Rates[epidemics], turn, p*q/float64(len(orderings)))
+			}
+		}
+	}
+	return odds
+}
+
+func testSet(cities ...CityName) Set {
+	set := Set{}
+	for _, city := range cities {
+		set.Add(city)
+	}
+	return set
+}
+
+func TestInfectionOddsMatchBruteForce(t *testing.T) {
+	decks := map[string]*InfectionDeck{
+		"two drawn": {
+			Drawn:      testSet("a", "b"),
+			Striations: []Set{testSet("c", "d", "e")},
+		},
+		"after an epidemic": {
+			Drawn:      testSet("a"),
+			Striations: []Set{testSet("b"), testSet("c", "d", "e")},
+		},
+		"one card at the bottom": {
+			Drawn:      testSet("e"),
+			Striations: []Set{testSet("a", "b"), testSet("c", "d"), testSet("f")},
+		},
+	}
+	outlooks := []DrawOutlook{
+		{Epidemics: [3]float64{1, 0, 0}, Rates: [3]int{2, 2, 2}},
+		{Epidemics: [3]float64{0.2, 0.5, 0.3}, Rates: [3]int{2, 3, 4}, CityCard: [3]float64{0.25, 0.1, 0}},
+		{Epidemics: [3]float64{0, 0, 1}, Rates: [3]int{3, 3, 3}},
+	}
+	for name, deck := range decks {
+		for i, outlook := range outlooks {
+			for _, cn := range []CityName{"a", "b", "c", "e", "f", "z"} {
+				for cubes := 0; cubes <= 3; cubes++ {
+					actual := deck.Odds(cn, cubes, outlook)
+					expected := bruteForceOdds(deck, cn, cubes, outlook)
+					if math.Abs(actual.AtLeastOne-expected.AtLeastOne) > 1e-9 ||
+						math.Abs(actual.AtLeastTwo-expected.AtLeastTwo) > 1e-9 ||
+						math.Abs(actual.Outbreak-expected.Outbreak) > 1e-9 {
+						t.Errorf("%v, outlook %d, %v with %d cubes: expected %+v, got %+v", name, i, cn, cubes, expected, actual)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEpidemicOddsAddUp(t *testing.T) {
+	_, deck, err := getTestCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	deck.DrawCard("a")
+	deck.DrawCard("b")
+	odds := deck.epidemicOdds()
+	if math.Abs(odds[0]+odds[1]+odds[2]-1.0) > 1e-9 {
+		t.Fatalf("The epidemic odds should add up to 1, got %v", odds)
+	}
+	if analysis := deck.EpidemicAnalysis(); math.Abs(odds[1]+2*odds[2]-analysis.FirstCardProbability-analysis.SecondCardProbability) > 1e-9 {
+		t.Fatalf("The expected number of epidemics should match the epidemic analysis, got %v", odds)
+	}
+}
+
+func TestNoInfectionOddsWhereNoCubesArePlaced(t *testing.T) {
+	gs := newTestGame(t)
+	if odds := gs.InfectionOdds("atlanta"); odds.AtLeastOne == 0 {
+		t.Fatalf("Expected atlanta to have a chance of being infected, got %+v", odds)
+	}
+
+	gs.Cures = CureTrack{Blue.Type: Eradicated}
+	if odds := gs.InfectionOdds("atlanta"); odds != (InfectionOdds{}) {
+		t.Fatalf("Eradicated diseases are not placed, got %+v", odds)
+	}
+	if _, err := gs.Infect("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if atlanta, _ := gs.GetCity("atlanta"); atlanta.TotalCubes() != 0 {
+		t.Fatal("Infecting should not place an eradicated disease")
+	}
+
+	gs.Cures = CureTrack{Blue.Type: Cured}
+	medic := gs.GameTurns.PlayerOrder[0]
+	medic.Character.Type = Medic
+	if err := gs.SetLocation(medic, "atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if odds := gs.InfectionOdds("atlanta"); odds != (InfectionOdds{}) {
+		t.Fatalf("The Medic keeps cured diseases out of their city, got %+v", odds)
+	}
+	if odds := gs.InfectionOdds("chicago"); odds.AtLeastOne == 0 {
+		t.Fatalf("Cured diseases should still infect cities without the Medic, got %+v", odds)
+	}
+}
@@ -0,0 +1,287 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EventType identifies one kind of state-mutating command.
+type EventType string
+
+const (
+	StartGameEvent        EventType = "start"
+	StartDrawEvent        EventType = "start-city-draw"
+	CityDrawEvent         EventType = "city-draw"
+	InfectEvent           EventType = "infect"
+	EpidemicEvent         EventType = "epidemic"
+	InfectionRateEvent    EventType = "infect-rate"
+	SetInfectionsEvent    EventType = "city-infect-level"
+	TreatEvent            EventType = "treat-disease"
+	GiveCardEvent         EventType = "give-card"
+	DiscardEvent          EventType = "discard"
+	QuarantineEvent       EventType = "quarantine"
+	RemoveQuarantineEvent EventType = "remove-quarantine"
+	MoveEvent             EventType = "move"
+	NextTurnEvent         EventType = "next-turn"
+	PanicEvent            EventType = "panic"
+	CureEvent             EventType = "cure"
+	PlayEventCardEvent    EventType = "play"
+	LocationEvent         EventType = "player-location"
+	PhaseEvent            EventType = "phase"
+	BuildStationEvent     EventType = "build-station"
+	TreatCubesEvent       EventType = "treat"
+	PlaceStationEvent     EventType = "station"
+	RemoveStationEvent    EventType = "remove-station"
+)
+
+// Event is a single successful change to the game state. Replaying the
+// events of a Journal in order against the initial setup yields exactly
+// the current state, including the infection deck striations and the
+// city deck probability model.
+type Event struct {
+	Type    EventType   `json:"type"`
+	Player  string      `json:"player,omitempty"`
+	To      string      `json:"to,omitempty"`
+	City    CityName    `json:"city,omitempty"`
+	Card    CardName    `json:"card,omitempty"`
+	Disease DiseaseType `json:"disease,omitempty"`
+	Cards   []CardName  `json:"cards,omitempty"`
+	Phase   TurnPhase   `json:"phase,omitempty"`
+	Value   int         `json:"value,omitempty"`
+	Action  ActionType  `json:"action,omitempty"`
+	// Correction marks changes made while ignoring the rules, whose actions
+	// are not counted.
+	Correction bool `json:"correction,omitempty"`
+}
+
+func (e Event) String() string {
+	switch e.Type {
+	case StartGameEvent, NextTurnEvent:
+		return string(e.Type)
+	case StartDrawEvent, CityDrawEvent:
+		return fmt.Sprintf("%v %v", e.Type, e.Card)
+	case GiveCardEvent:
+		return fmt.Sprintf("%v %v %v -> %v", e.Type, e.Card, e.Player, e.To)
+	case DiscardEvent, PlayEventCardEvent:
+		return fmt.Sprintf("%v %v %v", e.Type, e.Player, e.Card)
+	case PhaseEvent:
+		return fmt.Sprintf("%v %v", e.Type, e.Phase)
+	case MoveEvent:
+		if e.Action != "" {
+			return fmt.Sprintf("%v %v %v by %v", e.Type, e.Player, e.City, Move{e.Action, e.Card})
+		}
+		return fmt.Sprintf("%v %v %v", e.Type, e.Player, e.City)
+	case LocationEvent, BuildStationEvent:
+		return fmt.Sprintf("%v %v %v", e.Type, e.Player, e.City)
+	case TreatCubesEvent:
+		return fmt.Sprintf("%v %v %v %v", e.Type, e.Player, e.City, e.Disease)
+	case InfectionRateEvent:
+		return fmt.Sprintf("%v %v", e.Type, e.Value)
+	case SetInfectionsEvent, TreatEvent:
+		return fmt.Sprintf("%v %v %v %v", e.Type, e.City, e.Value, e.Disease)
+	case PanicEvent:
+		return fmt.Sprintf("%v %v %v", e.Type, e.City, PanicLevel(e.Value))
+	case CureEvent:
+		return fmt.Sprintf("%v %v %v %v", e.Type, e.Player, e.Disease, e.Cards)
+	default:
+		return fmt.Sprintf("%v %v", e.Type, e.City)
+	}
+}
+
+// apply runs the event against the given state, returning the outbreak it
+// caused, if any. The rules are not enforced, since games saved before
+// turn phases and actions existed did not follow them, but actions are
+// counted unless the event was a correction.
+func (e Event) apply(gs *GameState) (*OutbreakResult, error) {
+	var outbreak *OutbreakResult
+	var err error
+	ignoreRules, replaying := gs.IgnoreRules, gs.replaying
+	gs.IgnoreRules, gs.replaying = true, &e
+	defer func() { gs.IgnoreRules, gs.replaying = ignoreRules, replaying }()
+	switch e.Type {
+	case StartGameEvent:
+		gs.StartGame()
+	case StartDrawEvent:
+		err = gs.StartDrawCard(e.Card)
+	case CityDrawEvent:
+		err = gs.DrawCard(e.Card)
+	case InfectEvent:
+		outbreak, err = gs.Infect(e.City)
+	case EpidemicEvent:
+		outbreak, err = gs.Epidemic(e.City)
+	case InfectionRateEvent:
+		gs.SetInfectionRate(e.Value)
+	case SetInfectionsEvent:
+		err = gs.SetInfections(e.City, e.Disease, e.Value)
+	case TreatEvent:
+		err = gs.TreatInfections(e.City, e.Disease, e.Value)
+	case GiveCardEvent:
+		var from, to *Player
+		if from, err = gs.GameTurns.GetPlayer(e.Player); err != nil {
+			return nil, err
+		}
+		if to, err = gs.GameTurns.GetPlayer(e.To); err != nil {
+			return nil, err
+		}
+		err = gs.ExchangeCard(from, to, e.Card)
+	case DiscardEvent:
+		var player *Player
+		if player, err = gs.GameTurns.GetPlayer(e.Player); err != nil {
+			return nil, err
+		}
+		err = gs.Discard(player, e.Card)
+	case QuarantineEvent:
+		err = gs.Quarantine(e.City)
+	case RemoveQuarantineEvent:
+		err = gs.RemoveQuarantine(e.City)
+	case MoveEvent:
+		var player *Player
+		if player, err = gs.GameTurns.GetPlayer(e.Player); err != nil {
+			return nil, err
+		}
+		err = gs.MovePawn(player, e.City, e.Action, e.Card)
+	case LocationEvent:
+		var player *Player
+		if player, err = gs.GameTurns.GetPlayer(e.Player); err != nil {
+			return nil, err
+		}
+		err = gs.SetLocation(player, e.City)
+	case BuildStationEvent:
+		var player *Player
+		if player, err = gs.GameTurns.GetPlayer(e.Player); err != nil {
+			return nil, err
+		}
+		err = gs.BuildStation(player)
+	case TreatCubesEvent:
+		var player *Player
+		if player, err = gs.GameTurns.GetPlayer(e.Player); err != nil {
+			return nil, err
+		}
+		err = gs.Treat(player, e.Disease)
+	case PlaceStationEvent:
+		err = gs.PlaceStation(e.City)
+	case RemoveStationEvent:
+		err = gs.RemoveStation(e.City)
+	case PhaseEvent:
+		err = gs.SetPhase(e.Phase)
+	case NextTurnEvent:
+		_, err = gs.advanceTurn()
+	case PanicEvent:
+		err = gs.SetPanicLevel(e.City, PanicLevel(e.Value))
+	case CureEvent:
+		var player *Player
+		if player, err = gs.GameTurns.GetPlayer(e.Player); err != nil {
+			return nil, err
+		}
+		err = gs.Cure(player, e.Disease, e.Cards)
+	case PlayEventCardEvent:
+		var player *Player
+		if player, err = gs.GameTurns.GetPlayer(e.Player); err != nil {
+			return nil, err
+		}
+		err = gs.PlayEventCard(player, e.Card)
+	default:
+		err = fmt.Errorf("Unknown event type %v", e.Type)
+	}
+	return outbreak, err
+}
+
+// Journal is the append-only log of every event applied to a game since
+// its initial setup. The setup is kept verbatim (either the new game
+// settings or the snapshot the game was loaded from) so that the state can
+// be rebuilt from scratch at any point in the log.
+type Journal struct {
+	Setup    json.RawMessage `json:"setup,omitempty"`
+	Snapshot json.RawMessage `json:"snapshot,omitempty"`
+	Events   []Event         `json:"events"`
+	undone   []Event
+	revision int
+}
+
+func (j *Journal) record(ev Event) {
+	j.Events = append(j.Events, ev)
+	j.undone = nil
+	j.revision++
+}
+
+// Revision changes every time the journal records, undoes or redoes an
+// event, so callers can tell whether a command changed the game.
+func (j *Journal) Revision() int {
+	if j == nil {
+		return 0
+	}
+	return j.revision
+}
+
+// CanRedo reports whether there are undone events that can be re-applied.
+func (j *Journal) CanRedo() bool {
+	return len(j.undone) > 0
+}
+
+// rebuild creates a fresh game state from the journal's setup and replays
+// the given events against it. The returned state has no journal attached,
+// so the replayed events are not recorded a second time.
+func (j *Journal) rebuild(gameName string, events []Event) (*GameState, error) {
+	var gs *GameState
+	var err error
+	if j.Setup != nil {
+		gs, err = newGameFromSettings(j.Setup, gameName)
+	} else if j.Snapshot != nil {
+		gs, err = loadSnapshot(j.Snapshot)
+	} else {
+		err = fmt.Errorf("Journal has no initial setup to replay from")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, ev := range events {
+		if _, err := ev.apply(gs); err != nil {
+			return nil, fmt.Errorf("Could not replay event %v (%v): %v", i, ev, err)
+		}
+	}
+	return gs, nil
+}
+
+func (gs *GameState) record(ev Event) {
+	ev.Correction = gs.correcting()
+	if gs.Journal != nil {
+		gs.Journal.record(ev)
+	}
+}
+
+// Undo reverts the most recent event by rebuilding the game from its
+// initial setup and replaying every event but the last one.
+func (gs *GameState) Undo() (*Event, error) {
+	j := gs.Journal
+	if j == nil || len(j.Events) == 0 {
+		return nil, fmt.Errorf("Nothing to undo")
+	}
+	last := j.Events[len(j.Events)-1]
+	rebuilt, err := j.rebuild(gs.GameName, j.Events[:len(j.Events)-1])
+	if err != nil {
+		return nil, err
+	}
+	j.Events = j.Events[:len(j.Events)-1]
+	j.undone = append(j.undone, last)
+	j.revision++
+	rebuilt.Journal = j
+	*gs = *rebuilt
+	return &last, nil
+}
+
+// Redo re-applies the most recently undone event, returning it along with
+// the outbreak it caused, if any.
+func (gs *GameState) Redo() (*Event, *OutbreakResult, error) {
+	j := gs.Journal
+	if j == nil || !j.CanRedo() {
+		return nil, nil, fmt.Errorf("Nothing to redo")
+	}
+	ev := j.undone[len(j.undone)-1]
+	remaining := j.undone[:len(j.undone)-1]
+	outbreak, err := ev.apply(gs)
+	if err != nil {
+		return nil, nil, err
+	}
+	j.undone = remaining
+	return &ev, outbreak, nil
+}
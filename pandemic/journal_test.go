@@ -0,0 +1,104 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func newTestGame(t testing.TB) *GameState {
+	gs, err := NewGame("../data/pandemicboard.json", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gs
+}
+
+func marshalState(t *testing.T, gs *GameState) string {
+	data, err := json.Marshal(gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestUndoRestoresDecksAndProbabilityModel(t *testing.T) {
+	gs := newTestGame(t)
+	if _, err := gs.Infect("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	before := marshalState(t, gs)
+
+	if err := gs.DrawCard("paris"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.Epidemic("tokyo"); err != nil {
+		t.Fatal(err)
+	}
+	if gs.CityDeck.ProbabilityModel.EpidemicsDrawn != 1 {
+		t.Fatal("Expected the epidemic to be recorded in the probability model")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := gs.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if after := marshalState(t, gs); after != before {
+		t.Fatalf("Undo did not restore the previous state:\nbefore %v\nafter  %v", before, after)
+	}
+	if len(gs.Journal.Events) != 1 {
+		t.Fatalf("Expected 1 event left in the journal, got %v", len(gs.Journal.Events))
+	}
+}
+
+func TestRedoReappliesUndoneEvents(t *testing.T) {
+	gs := newTestGame(t)
+	if _, err := gs.Infect("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.DrawCard("paris"); err != nil {
+		t.Fatal(err)
+	}
+	expected := marshalState(t, gs)
+
+	gs.Undo()
+	gs.Undo()
+	if _, _, err := gs.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := gs.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := marshalState(t, gs); actual != expected {
+		t.Fatalf("Redo did not restore the state:\nexpected %v\nactual   %v", expected, actual)
+	}
+	if _, _, err := gs.Redo(); err == nil {
+		t.Fatal("Expected an error when there is nothing to redo")
+	}
+}
+
+func TestNewEventClearsRedo(t *testing.T) {
+	gs := newTestGame(t)
+	gs.Infect("atlanta")
+	gs.Undo()
+	if !gs.Journal.CanRedo() {
+		t.Fatal("Expected to be able to redo after an undo")
+	}
+	gs.Infect("paris")
+	if gs.Journal.CanRedo() {
+		t.Fatal("A new event should discard undone events")
+	}
+}
+
+func TestUndoKeepsPlayerPointers(t *testing.T) {
+	gs := newTestGame(t)
+	gs.NextTurn()
+	gs.Undo()
+	cur, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur.Player != gs.GameTurns.PlayerOrder[0] {
+		t.Fatal("Current turn should reference the first player after undo")
+	}
+}
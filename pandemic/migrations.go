@@ -0,0 +1,276 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// SchemaVersion is the version of the persisted game format written by this
+// build. Documents without a schema_version are version 0.
+const SchemaVersion = 5
+
+type document map[string]interface{}
+
+// A migration upgrades a snapshot of the GameState from one schema version
+// to the next. migrations[i] upgrades version i to version i+1.
+type migration struct {
+	description string
+	migrate     func(doc document) error
+}
+
+var migrations = []migration{
+	{"reference players and cards by ID", linkByID},
+	{"rename isstarted to is_started", renameIsStarted},
+	{"add the disease cube supply", addDiseaseSupply},
+	{"track cubes per disease", cubesPerDisease},
+	{"describe the base game diseases", describeBaseDiseases},
+}
+
+// linkByID rewrites the shape written before the serialization layer
+// existed: turns held full copies of their player and cards, and the city
+// deck and player hands had untagged field names.
+func linkByID(doc document) error {
+	if turns, ok := doc["game_turns"].(map[string]interface{}); ok {
+		if players, ok := turns["player_order"].([]interface{}); ok {
+			for _, player := range players {
+				if player, ok := player.(map[string]interface{}); ok {
+					renameKey(player, "Cards", "cards")
+				}
+			}
+		}
+		if turnList, ok := turns["turns"].([]interface{}); ok {
+			for _, turn := range turnList {
+				turn, ok := turn.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if player, ok := turn["player"].(map[string]interface{}); ok {
+					turn["player"] = player["human_name"]
+				}
+				turn["drawn_cards"] = cardIDs(turn["drawn_cards"])
+			}
+		}
+	}
+	if deck, ok := doc["city_deck"].(map[string]interface{}); ok {
+		renameKey(deck, "All", "all")
+		renameKey(deck, "Drawn", "drawn")
+		renameKey(deck, "StartCities", "start_cities")
+		renameKey(deck, "ProbabilityModel", "probability_model")
+		deck["drawn"] = cardIDs(deck["drawn"])
+		deck["start_cities"] = cardIDs(deck["start_cities"])
+	}
+	return nil
+}
+
+func renameIsStarted(doc document) error {
+	renameKey(doc, "isstarted", "is_started")
+	return nil
+}
+
+// addDiseaseSupply fills the supply with every cube that is not on a city.
+// At this version every cube on a city was of the city's own disease.
+func addDiseaseSupply(doc document) error {
+	supply := map[string]interface{}{}
+	for _, dt := range documentDiseases(doc) {
+		supply[dt] = float64(CubesPerDisease)
+	}
+	for _, city := range documentCities(doc) {
+		disease, _ := city["disease"].(string)
+		cubes, _ := city["num_infections"].(float64)
+		left, ok := supply[disease].(float64)
+		if !ok {
+			left = CubesPerDisease
+		}
+		supply[disease] = left - cubes
+	}
+	doc["disease_supply"] = supply
+	return nil
+}
+
+// cubesPerDisease replaces the single cube count of each city with a count
+// per disease, all of them of the city's own disease.
+func cubesPerDisease(doc document) error {
+	for _, city := range documentCities(doc) {
+		cubes, _ := city["num_infections"].(float64)
+		delete(city, "num_infections")
+		if cubes > 0 {
+			disease, _ := city["disease"].(string)
+			city["cubes"] = map[string]interface{}{disease: cubes}
+		}
+	}
+	return nil
+}
+
+// baseDiseaseIcons are the icons the view used for the base game diseases
+// before they became part of the disease definitions.
+var baseDiseaseIcons = map[string]string{
+	"Yellow": "\U0001f49b",
+	"Blue":   "\U0001f499",
+	"Red":    "\U0001F6D1",
+	"Black":  "\u26ab",
+	"Faded":  "\U0001f608",
+}
+
+// describeBaseDiseases gives the diseases of older games the icons they
+// were shown with. Cube counts and cards to cure default to the base game.
+func describeBaseDiseases(doc document) error {
+	list, _ := doc["disease_data"].([]interface{})
+	for _, data := range list {
+		data, ok := data.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dt, _ := data["type"].(string)
+		if icon, ok := baseDiseaseIcons[dt]; ok {
+			if _, ok := data["icon"]; !ok {
+				data["icon"] = icon
+			}
+		}
+	}
+	return nil
+}
+
+// documentDiseases lists the disease types of the document, falling back to
+// the base game diseases for documents without any.
+func documentDiseases(doc document) []string {
+	diseases := []string{}
+	list, _ := doc["disease_data"].([]interface{})
+	for _, data := range list {
+		if data, ok := data.(map[string]interface{}); ok {
+			if dt, ok := data["type"].(string); ok {
+				diseases = append(diseases, dt)
+			}
+		}
+	}
+	if len(diseases) == 0 {
+		diseases = []string{"Yellow", "Red", "Black", "Blue", "Faded"}
+	}
+	return diseases
+}
+
+func documentCities(doc document) []map[string]interface{} {
+	cities := []map[string]interface{}{}
+	list, _ := doc["cities"].([]interface{})
+	for _, city := range list {
+		if city, ok := city.(map[string]interface{}); ok {
+			cities = append(cities, city)
+		}
+	}
+	return cities
+}
+
+func renameKey(m map[string]interface{}, from, to string) {
+	if v, ok := m[from]; ok {
+		delete(m, from)
+		m[to] = v
+	}
+}
+
+// cardIDs replaces full card objects with their card names.
+func cardIDs(cards interface{}) []interface{} {
+	ids := []interface{}{}
+	list, _ := cards.([]interface{})
+	for _, card := range list {
+		obj, ok := card.(map[string]interface{})
+		if !ok {
+			ids = append(ids, card)
+			continue
+		}
+		var c CityCard
+		data, _ := json.Marshal(obj)
+		json.Unmarshal(data, &c)
+		ids = append(ids, c.Name().String())
+	}
+	return ids
+}
+
+func documentVersion(doc document) (int, error) {
+	raw, ok := doc["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+	version, ok := raw.(float64)
+	if !ok {
+		return 0, fmt.Errorf("schema_version should be a number, got %v", raw)
+	}
+	return int(version), nil
+}
+
+// migrateSnapshot upgrades a GameState snapshot document in place.
+func migrateSnapshot(doc document) error {
+	version, err := documentVersion(doc)
+	if err != nil {
+		return err
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("Save has schema version %v, newer than the supported %v", version, SchemaVersion)
+	}
+	for ; version < SchemaVersion; version++ {
+		if err := migrations[version].migrate(doc); err != nil {
+			return fmt.Errorf("Could not migrate to version %v (%v): %v", version+1, migrations[version].description, err)
+		}
+	}
+	doc["schema_version"] = SchemaVersion
+	return nil
+}
+
+// Migrate upgrades a saved game, either a snapshot or an event-sourced
+// save, to the current schema. It returns the upgraded document along with
+// the version the document had before.
+func Migrate(data []byte) ([]byte, int, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, err
+	}
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version == SchemaVersion {
+		return data, version, nil
+	}
+	if _, ok := doc["events"]; ok {
+		// event-sourced saves only change through the snapshot they were
+		// started from, if any.
+		if snapshot, ok := doc["snapshot"].(map[string]interface{}); ok {
+			if err := migrateSnapshot(snapshot); err != nil {
+				return nil, version, err
+			}
+		}
+		if version > SchemaVersion {
+			return nil, version, fmt.Errorf("Save has schema version %v, newer than the supported %v", version, SchemaVersion)
+		}
+		doc["schema_version"] = SchemaVersion
+	} else if err := migrateSnapshot(doc); err != nil {
+		return nil, version, err
+	}
+	migrated, err := json.Marshal(doc)
+	return migrated, version, err
+}
+
+// MigrateFile rewrites a saved game in place using the current schema. The
+// original is kept next to it with a .v<version>.bak suffix. The returned
+// backup name is empty if the file was already up to date.
+func MigrateFile(filename string) (int, string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return 0, "", err
+	}
+	migrated, version, err := Migrate(data)
+	if err != nil {
+		return version, "", err
+	}
+	if version == SchemaVersion {
+		return version, "", nil
+	}
+	backup := fmt.Sprintf("%v.v%v.bak", filename, version)
+	if _, err := os.Stat(backup); err == nil {
+		return version, "", fmt.Errorf("Backup %v already exists", backup)
+	}
+	if err := ioutil.WriteFile(backup, data, 0644); err != nil {
+		return version, "", err
+	}
+	return version, backup, writeFileAtomic(filename, migrated)
+}
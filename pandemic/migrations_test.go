@@ -0,0 +1,149 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// legacySnapshot rewrites a current snapshot the way version 0 wrote it:
+// full objects for turn players and cards, untagged city deck and hand
+// fields, a single cube count per city, no disease supply and no schema
+// version.
+func legacySnapshot(t *testing.T, gs *GameState) []byte {
+	data, err := json.Marshal(gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	json.Unmarshal(data, &doc)
+	delete(doc, "schema_version")
+	delete(doc, "disease_supply")
+	for _, data := range doc["disease_data"].([]interface{}) {
+		delete(data.(map[string]interface{}), "icon")
+	}
+	for _, rawCity := range doc["cities"].([]interface{}) {
+		city := rawCity.(map[string]interface{})
+		cubes, _ := city["cubes"].(map[string]interface{})
+		city["num_infections"] = cubes[city["disease"].(string)]
+		if cubes == nil {
+			city["num_infections"] = 0
+		}
+		delete(city, "cubes")
+	}
+	renameKey(doc, "is_started", "isstarted")
+	turns := doc["game_turns"].(map[string]interface{})
+	players := turns["player_order"].([]interface{})
+	for _, player := range players {
+		renameKey(player.(map[string]interface{}), "cards", "Cards")
+	}
+	for _, rawTurn := range turns["turns"].([]interface{}) {
+		turn := rawTurn.(map[string]interface{})
+		for _, player := range players {
+			if player.(map[string]interface{})["human_name"] == turn["player"] {
+				turn["player"] = player
+			}
+		}
+		drawn := []interface{}{}
+		for _, name := range turn["drawn_cards"].([]interface{}) {
+			drawn = append(drawn, map[string]interface{}{"city_name": name, "is_epidemic": false})
+		}
+		turn["drawn_cards"] = drawn
+	}
+	deck := doc["city_deck"].(map[string]interface{})
+	drawn := []interface{}{}
+	for _, name := range deck["drawn"].([]interface{}) {
+		if name == "epidemic" {
+			drawn = append(drawn, map[string]interface{}{"is_epidemic": true})
+		} else {
+			drawn = append(drawn, map[string]interface{}{"city_name": name, "is_epidemic": false})
+		}
+	}
+	deck["drawn"] = drawn
+	renameKey(deck, "all", "All")
+	renameKey(deck, "drawn", "Drawn")
+	renameKey(deck, "start_cities", "StartCities")
+	renameKey(deck, "probability_model", "ProbabilityModel")
+	legacy, _ := json.Marshal(doc)
+	return legacy
+}
+
+func TestLoadVersionZeroSnapshot(t *testing.T) {
+	gs := newTestGame(t)
+	gs.StartGame()
+	// Version 0 games were played without turn phases.
+	gs.IgnoreRules = true
+	playTestTurns(t, gs)
+	filename := filepath.Join(t.TempDir(), "legacy.json")
+	if err := ioutil.WriteFile(filename, legacySnapshot(t, gs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadGame(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLinked(t, loaded)
+	if !loaded.IsStarted {
+		t.Fatal("isstarted was not migrated")
+	}
+	if expected, actual := marshalState(t, gs), marshalState(t, loaded); expected != actual {
+		t.Fatalf("Legacy snapshot loaded differently:\nexpected %v\nactual   %v", expected, actual)
+	}
+}
+
+func TestMigrateFileKeepsBackup(t *testing.T) {
+	gs := newTestGame(t)
+	playTestTurns(t, gs)
+	filename := filepath.Join(t.TempDir(), "legacy.json")
+	legacy := legacySnapshot(t, gs)
+	ioutil.WriteFile(filename, legacy, 0644)
+
+	version, backup, err := MigrateFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 0 || backup != filename+".v0.bak" {
+		t.Fatalf("Expected to migrate from version 0 with a backup, got %v %v", version, backup)
+	}
+	if original, _ := ioutil.ReadFile(backup); string(original) != string(legacy) {
+		t.Fatal("Backup should contain the original document")
+	}
+	migrated, _ := ioutil.ReadFile(filename)
+	var doc map[string]interface{}
+	json.Unmarshal(migrated, &doc)
+	if doc["schema_version"] != float64(SchemaVersion) {
+		t.Fatalf("Expected schema version %v, got %v", SchemaVersion, doc["schema_version"])
+	}
+
+	version, backup, err = MigrateFile(filename)
+	if err != nil || version != SchemaVersion || backup != "" {
+		t.Fatalf("Migrating a current file should be a no-op, got %v %v %v", version, backup, err)
+	}
+}
+
+func TestMigrateSavedGameWithSnapshot(t *testing.T) {
+	gs := newTestGame(t)
+	playTestTurns(t, gs)
+	saved := SavedGame{GameName: "test", Snapshot: legacySnapshot(t, gs), Events: []Event{{Type: InfectEvent, City: "atlanta"}}}
+	data, _ := json.Marshal(saved)
+	filename := filepath.Join(t.TempDir(), "saved.json")
+	ioutil.WriteFile(filename, data, 0644)
+
+	loaded, err := LoadGame(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLinked(t, loaded)
+	city, _ := loaded.GetCity("atlanta")
+	if city.CubesOf(Blue.Type) != 2 {
+		t.Fatalf("Expected the saved event to be replayed on the migrated snapshot")
+	}
+}
+
+func TestRejectNewerSchema(t *testing.T) {
+	if _, _, err := Migrate([]byte(`{"schema_version": 99, "cities": []}`)); err == nil {
+		t.Fatal("Expected an error for a schema newer than supported")
+	}
+}
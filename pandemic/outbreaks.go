@@ -0,0 +1,110 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutbreakEffect is what an outbreak did to one neighboring city.
+type OutbreakEffect string
+
+const (
+	// The neighbor gained a cube.
+	CubeAdded OutbreakEffect = "cube"
+	// The neighbor already had 3 cubes and outbreaked in turn.
+	Chained OutbreakEffect = "outbreak"
+	// The neighbor's quarantine stopped the cube and was removed.
+	QuarantineConsumed OutbreakEffect = "quarantine-consumed"
+	// The neighbor's quarantine stopped the cube and stayed, because the
+	// quarantine specialist is there.
+	QuarantineHeld OutbreakEffect = "quarantine-held"
+	// The Medic is in the neighbor and the disease is cured.
+	MedicProtected OutbreakEffect = "medic-protected"
+	// The neighbor already outbreaked in this cascade.
+	AlreadyOutbreaked OutbreakEffect = "already-outbreaked"
+)
+
+// OutbreakStep is one city spilling over into one of its neighbors. Depth
+// is 0 for the neighbors of the origin, 1 for the neighbors of a city that
+// chained from the origin and so on.
+type OutbreakStep struct {
+	From   CityName       `json:"from"`
+	To     CityName       `json:"to"`
+	Depth  int            `json:"depth"`
+	Effect OutbreakEffect `json:"effect"`
+}
+
+// OutbreakResult describes a whole outbreak cascade, from the city that
+// first outbreaked to the last neighbor that was hit.
+type OutbreakResult struct {
+	Origin              CityName       `json:"origin"`
+	Disease             DiseaseType    `json:"disease"`
+	Chain               []OutbreakStep `json:"chain"`
+	Infected            []CityName     `json:"infected"`
+	Chained             []CityName     `json:"chained"`
+	QuarantinesConsumed []CityName     `json:"quarantines_consumed"`
+	// Outbreaking cities that started rioting and lost their research
+	// station.
+	StationsLost []CityName `json:"stations_lost,omitempty"`
+}
+
+func newOutbreakResult(origin CityName, dt DiseaseType) *OutbreakResult {
+	return &OutbreakResult{
+		Origin:              origin,
+		Disease:             dt,
+		Chain:               []OutbreakStep{},
+		Infected:            []CityName{},
+		Chained:             []CityName{},
+		QuarantinesConsumed: []CityName{},
+	}
+}
+
+func (o *OutbreakResult) add(step OutbreakStep) {
+	o.Chain = append(o.Chain, step)
+	switch step.Effect {
+	case CubeAdded:
+		o.Infected = append(o.Infected, step.To)
+	case Chained:
+		o.Infected = append(o.Infected, step.To)
+		o.Chained = append(o.Chained, step.To)
+	case QuarantineConsumed:
+		o.QuarantinesConsumed = append(o.QuarantinesConsumed, step.To)
+	}
+}
+
+// Outbreaks is the number of outbreaks the cascade added to the outbreak
+// track: the origin plus every city that chained.
+func (o *OutbreakResult) Outbreaks() int {
+	return 1 + len(o.Chained)
+}
+
+// Lines renders the cascade one step per line, indented by depth.
+func (o *OutbreakResult) Lines() []string {
+	lines := []string{fmt.Sprintf("Outbreak!!! %v %v (%v outbreaks, %v cities infected)", o.Disease, o.Origin, o.Outbreaks(), len(o.Infected))}
+	for _, step := range o.Chain {
+		var effect string
+		switch step.Effect {
+		case CubeAdded:
+			effect = fmt.Sprintf("+1 %v cube", o.Disease)
+		case Chained:
+			effect = "outbreak!"
+		case QuarantineConsumed:
+			effect = "stopped by quarantine, quarantine removed"
+		case QuarantineHeld:
+			effect = "stopped by quarantine specialist"
+		case MedicProtected:
+			effect = "protected by the medic"
+		case AlreadyOutbreaked:
+			effect = "already outbreaked"
+		}
+		lines = append(lines, fmt.Sprintf("%v%v -> %v: %v", strings.Repeat("  ", step.Depth+1), step.From, step.To, effect))
+	}
+	for _, cn := range o.StationsLost {
+		lines = append(lines, fmt.Sprintf("%v is rioting and lost its research station", cn))
+	}
+	return lines
+}
+
+func (o *OutbreakResult) String() string {
+	return strings.Join(o.Lines(), "\n")
+}
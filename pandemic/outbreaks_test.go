@@ -0,0 +1,128 @@
+package pandemic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOutbreakResultDescribesCascade(t *testing.T) {
+	gs := newTestGame(t)
+	for _, cn := range []CityName{"atlanta", "chicago"} {
+		if err := gs.SetInfections(cn, "", 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gs.Quarantine("washington"); err != nil {
+		t.Fatal(err)
+	}
+	result, err := gs.Infect("atlanta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil {
+		t.Fatal("Expected an outbreak")
+	}
+	if result.Origin != "atlanta" || result.Outbreaks() != 2 || gs.Outbreaks != 2 {
+		t.Fatalf("Expected 2 outbreaks from atlanta, got %+v", result)
+	}
+	if !reflect.DeepEqual(result.Chained, []CityName{"chicago"}) {
+		t.Fatalf("Expected chicago to chain, got %v", result.Chained)
+	}
+	if !reflect.DeepEqual(result.QuarantinesConsumed, []CityName{"washington"}) {
+		t.Fatalf("Expected washington's quarantine to be consumed, got %v", result.QuarantinesConsumed)
+	}
+	expectedInfected := []CityName{"chicago", "montreal", "mexicocity", "losangeles", "sanfrancisco", "miami"}
+	if !reflect.DeepEqual(result.Infected, expectedInfected) {
+		t.Fatalf("Expected %v to gain cubes, got %v", expectedInfected, result.Infected)
+	}
+	washington, _ := gs.GetCity("washington")
+	if washington.Quarantined || washington.TotalCubes() != 0 {
+		t.Fatal("The quarantine should have stopped the cube and been removed")
+	}
+	if step := result.Chain[1]; step.From != "chicago" || step.To != "montreal" || step.Depth != 1 {
+		t.Fatalf("Expected chicago's neighbors to follow it in the chain, got %+v", step)
+	}
+	if len(result.Lines()) != len(result.Chain)+1 {
+		t.Fatal("Expected a heading plus one line per step")
+	}
+}
+
+func TestOutbreaksAreKeptInTurnHistory(t *testing.T) {
+	gs := newTestGame(t)
+	if _, err := gs.Infect("paris"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfections("atlanta", "", 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.Epidemic("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	if len(gs.InfectionDeck.Drawn) != 0 {
+		t.Fatal("The infection discard pile should be shuffled back even when the epidemic outbreaks")
+	}
+	cur, _ := gs.GameTurns.CurrentTurn()
+	if len(cur.Outbreaks) != 1 || cur.Outbreaks[0].Origin != "atlanta" {
+		t.Fatalf("Expected the outbreak in the current turn, got %v", cur.Outbreaks)
+	}
+
+	loaded := roundTrip(t, gs)
+	loadedTurn, _ := loaded.GameTurns.CurrentTurn()
+	if !reflect.DeepEqual(loadedTurn.Outbreaks, cur.Outbreaks) {
+		t.Fatal("Outbreaks should survive saving and loading")
+	}
+	if _, err := gs.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := gs.GameTurns.CurrentTurn(); len(cur.Outbreaks) != 0 {
+		t.Fatal("Undoing the epidemic should remove its outbreak from the turn")
+	}
+}
+
+func TestOutbreaksSpreadTheOutbreakingDisease(t *testing.T) {
+	gs := newTestGame(t)
+	if err := gs.SetInfections("atlanta", "", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfections("miami", "", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.Infect("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	miami, _ := gs.GetCity("miami")
+	if miami.CubesOf(Yellow.Type) != 1 || miami.CubesOf(Blue.Type) != 1 {
+		t.Fatalf("Expected miami to hold 1 yellow and 1 blue cube, got %v", miami.Cubes)
+	}
+
+	if err := gs.SetInfections("miami", Blue.Type, 3); err != nil {
+		t.Fatal(err)
+	}
+	if !gs.CanOutbreakWith("miami", Blue.Type) || gs.CanOutbreakWith("miami", Red.Type) {
+		t.Fatal("Miami should be able to outbreak blue next to atlanta, but not red")
+	}
+	if err := gs.TreatInfections("atlanta", "", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfections("washington", "", 3); err != nil {
+		t.Fatal(err)
+	}
+	result, err := gs.Infect("washington")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Chained, []CityName{"miami"}) || result.Disease != Blue.Type {
+		t.Fatalf("Expected a blue outbreak to chain through miami, got %+v", result)
+	}
+	bogota, _ := gs.GetCity("bogota")
+	if bogota.CubesOf(Blue.Type) != 1 || bogota.CubesOf(Yellow.Type) != 0 {
+		t.Fatalf("Miami's blue outbreak should put a blue cube on bogota, got %v", bogota.Cubes)
+	}
+
+	if err := gs.TreatInfections("miami", Blue.Type, 3); err != nil {
+		t.Fatal(err)
+	}
+	if miami.CubesOf(Blue.Type) != 0 || miami.CubesOf(Yellow.Type) != 1 {
+		t.Fatalf("Treating blue should leave the yellow cube, got %v", miami.Cubes)
+	}
+}
@@ -11,6 +11,26 @@ func (p PanicLevel) CanBuildResearchStations() bool {
 	return int(p) < 2
 }
 
+// CanMoveInto is false for fallen cities, which players can no longer enter.
+func (p PanicLevel) CanMoveInto() bool {
+	return p != Fallen
+}
+
+// CityCardsUsable is false for fallen cities. Their city cards are dead
+// cards in a player's hand and do not count towards a cure.
+func (p PanicLevel) CityCardsUsable() bool {
+	return p != Fallen
+}
+
+// Raise returns the next level on the panic track, which is where a city
+// ends up after it outbreaks. Fallen cities stay fallen.
+func (p PanicLevel) Raise() PanicLevel {
+	if p >= Fallen {
+		return Fallen
+	}
+	return p + 1
+}
+
 const (
 	Nothing = PanicLevel(iota)
 	Unstable
@@ -50,7 +70,7 @@ func (pl PanicLevel) String() string {
 	return got
 }
 
-func (pl *PanicLevel) MarshalJSON() ([]byte, error) {
+func (pl PanicLevel) MarshalJSON() ([]byte, error) {
 	return json.Marshal(pl.String())
 }
 
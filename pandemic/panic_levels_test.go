@@ -0,0 +1,98 @@
+package pandemic
+
+import "testing"
+
+func TestOutbreaksRaisePanic(t *testing.T) {
+	gs := newTestGame(t)
+	for _, cn := range []CityName{"atlanta", "chicago"} {
+		if err := gs.SetInfections(cn, "", 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gs.SetPanicLevel("chicago", Collapsing); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.Infect("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[CityName]PanicLevel{
+		"atlanta":    Unstable,
+		"chicago":    Fallen,
+		"washington": Nothing,
+	}
+	for cn, level := range expected {
+		city, _ := gs.GetCity(cn)
+		if city.PanicLevel != level {
+			t.Fatalf("Expected %v to be %v, got %v", cn, level, city.PanicLevel)
+		}
+	}
+	if gs.Outbreaks != 2 {
+		t.Fatalf("Expected a chained outbreak, got %v outbreaks", gs.Outbreaks)
+	}
+	if Fallen.Raise() != Fallen {
+		t.Fatal("Fallen cities should stay fallen")
+	}
+}
+
+func TestFallenCities(t *testing.T) {
+	gs := newTestGame(t)
+	if err := gs.SetPanicLevel("paris", Fallen); err != nil {
+		t.Fatal(err)
+	}
+	player := gs.GameTurns.PlayerOrder[0]
+	if err := gs.MovePlayer(player, "paris"); err == nil {
+		t.Fatal("Players should not be able to move into a fallen city")
+	}
+	if player.Location == "paris" {
+		t.Fatal("A rejected move should not change the location")
+	}
+	gs.IgnoreRules = true
+	if err := gs.SetLocation(player, "paris"); err != nil || player.Location != "paris" {
+		t.Fatalf("Ignoring the rules should allow correcting a location to a fallen city: %v", err)
+	}
+	gs.IgnoreRules = false
+	if _, err := gs.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := gs.Redo(); err != nil {
+		t.Fatalf("Replaying a move into a fallen city should not fail: %v", err)
+	}
+	if _, err := gs.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if Fallen.CanBuildResearchStations() || !Unstable.CanBuildResearchStations() {
+		t.Fatal("Research stations can only be built in stable or unstable cities")
+	}
+
+	if _, err := gs.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	paris, _ := gs.GetCity("paris")
+	if paris.PanicLevel != Nothing {
+		t.Fatalf("Undo should restore the panic level, got %v", paris.PanicLevel)
+	}
+}
+
+func TestGetPanicLevelByPrefix(t *testing.T) {
+	cases := map[string]PanicLevel{
+		"0":   Nothing,
+		"5":   Fallen,
+		"fal": Fallen,
+		"Col": Collapsing,
+		"u":   Unstable,
+	}
+	for entry, expected := range cases {
+		level, err := GetPanicLevelByPrefix(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if level != expected {
+			t.Fatalf("Expected %v for %q, got %v", expected, entry, level)
+		}
+	}
+	for _, entry := range []string{"riot", "6", "xyz"} {
+		if _, err := GetPanicLevelByPrefix(entry); err == nil {
+			t.Fatalf("Expected an error for %q", entry)
+		}
+	}
+}
@@ -32,12 +32,30 @@ const (
 	ContingencyPlanner    = "ContingencyPlanner"
 )
 
+// CharacterTypes lists every character of the base game and Legacy.
+var CharacterTypes = []CharacterType{
+	Medic, Dispatcher, Researcher, Scientist, Civilian, QuarantineSpecialist,
+	Colonel, OperationsExpert, Generalist, Soldier, Virologist, Epidemiologist,
+	GeneSplicer, FirstResponder, Pharmacist, LocalLiason, FieldDirector, Pilot,
+	FieldOperative, Troubleshooter, Archivist, ContainmentSpecialist,
+	ContingencyPlanner,
+}
+
+func (c CharacterType) IsValid() bool {
+	for _, ct := range CharacterTypes {
+		if ct == c {
+			return true
+		}
+	}
+	return false
+}
+
 type Player struct {
-	HumanName  string     `json:"human_name"`
-	Character  *Character `json:"character"`
-	Location   CityName   `json:"location"`
-	StartCards []CardName `json:"start_cards"`
-	Cards      []*CityCard
+	HumanName  string      `json:"human_name"`
+	Character  *Character  `json:"character"`
+	Location   CityName    `json:"location"`
+	StartCards []CardName  `json:"start_cards"`
+	Cards      []*CityCard `json:"cards"`
 }
 
 func (p *Player) Discard(cardName CardName) error {
@@ -54,6 +72,35 @@ func (p *Player) Discard(cardName CardName) error {
 	return nil
 }
 
+func (p *Player) HasCard(name CardName) bool {
+	for _, card := range p.Cards {
+		if card.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCityCard is true if the player holds the card and it is a city card.
+func (p *Player) HasCityCard(name CardName) bool {
+	for _, card := range p.Cards {
+		if card.Name() == name && card.IsCity() {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Player) CityCards() []CardName {
+	cards := []CardName{}
+	for _, card := range p.Cards {
+		if card.IsCity() {
+			cards = append(cards, card.Name())
+		}
+	}
+	return cards
+}
+
 func (p *Player) SetLocation(location CityName) error {
 	p.Location = location
 	return nil
@@ -63,4 +110,5 @@ type Character struct {
 	Name        string        `json:"name"`
 	Type        CharacterType `json:"type"`
 	TurnMessage string        `json:"turn_message"`
+	Scars       []string      `json:"scars,omitempty"`
 }
@@ -0,0 +1,152 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteStep is one move of a route.
+type RouteStep struct {
+	Move
+	To CityName
+}
+
+// Route is the cheapest way found for a player to get from one city to
+// another, each step costing one action.
+type Route struct {
+	From  CityName
+	To    CityName
+	Steps []RouteStep
+}
+
+func (r *Route) Actions() int {
+	return len(r.Steps)
+}
+
+// Cards lists the cards the route discards.
+func (r *Route) Cards() []CardName {
+	cards := []CardName{}
+	for _, step := range r.Steps {
+		if step.Card != "" {
+			cards = append(cards, step.Card)
+		}
+	}
+	return cards
+}
+
+func (r *Route) String() string {
+	if len(r.Steps) == 0 {
+		return fmt.Sprintf("Already in %v", r.To)
+	}
+	steps := []string{}
+	for _, step := range r.Steps {
+		steps = append(steps, fmt.Sprintf("%v %v", step.Move, step.To))
+	}
+	return fmt.Sprintf("%v -> %v in %v actions: %v", r.From, r.To, r.Actions(), strings.Join(steps, ", "))
+}
+
+// routeState is a city reached with a set of the player's cards already
+// spent, one bit per card, and whether the Operations Expert's flight from a
+// research station, allowed once per turn, has been taken.
+type routeState struct {
+	city          int
+	spent         int
+	stationFlight bool
+}
+
+// PlanRoute finds the fewest actions the player needs to get from one city
+// to the other, by driving, shuttling between research stations, flying
+// with the city cards in their hand, and with the Pilot's and Operations
+// Expert's flights. Every card is spent at most once, the Operations
+// Expert flies from a research station at most once and fallen cities are
+// avoided.
+func (gs *GameState) PlanRoute(player *Player, from, to CityName) (*Route, error) {
+	board := gs.Board()
+	start, ok := board.index[from]
+	if !ok {
+		return nil, fmt.Errorf("%v is not a city of the board", from)
+	}
+	goal, ok := board.index[to]
+	if !ok {
+		return nil, fmt.Errorf("%v is not a city of the board", to)
+	}
+	cities := board.cities
+	cards := []CardName{}
+	for _, card := range player.CityCards() {
+		if city, err := gs.GetCity(CityName(card)); err == nil && city.PanicLevel.CityCardsUsable() {
+			cards = append(cards, card)
+		}
+	}
+	pilot := player.HasAbility(FliesWithinTwo)
+	opsExpert := player.HasAbility(FliesFromStation)
+
+	type visit struct {
+		from routeState
+		step RouteStep
+	}
+	visited := map[routeState]visit{}
+	first := routeState{city: start}
+	visited[first] = visit{}
+	queue := []routeState{first}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur.city == goal {
+			route := &Route{From: from, To: to}
+			for state := cur; state != first; state = visited[state].from {
+				route.Steps = append([]RouteStep{visited[state].step}, route.Steps...)
+			}
+			return route, nil
+		}
+		next := func(city, spent int, move Move) {
+			state := routeState{city: city, spent: spent, stationFlight: cur.stationFlight || move.Action == StationFlightAction}
+			if _, seen := visited[state]; seen || !cities[city].PanicLevel.CanMoveInto() {
+				return
+			}
+			visited[state] = visit{from: cur, step: RouteStep{Move: move, To: board.names[city]}}
+			queue = append(queue, state)
+		}
+		for _, neighbor := range board.adjacency[cur.city] {
+			next(neighbor, cur.spent, Move{Action: DriveAction})
+		}
+		if cities[cur.city].ResearchStation {
+			for i, city := range cities {
+				if city.ResearchStation && i != cur.city {
+					next(i, cur.spent, Move{Action: ShuttleFlightAction})
+				}
+			}
+		}
+		if pilot {
+			for _, neighbor := range board.adjacency[cur.city] {
+				for _, twoAway := range board.adjacency[neighbor] {
+					next(twoAway, cur.spent, Move{Action: PilotFlightAction})
+				}
+			}
+		}
+		for c, card := range cards {
+			bit := 1 << uint(c)
+			if cur.spent&bit != 0 {
+				continue
+			}
+			spent := cur.spent | bit
+			if dest, ok := board.index[CityName(card)]; ok && dest != cur.city {
+				next(dest, spent, Move{Action: DirectFlightAction, Card: card})
+			}
+			charter := CityName(card) == board.names[cur.city]
+			stationFlight := opsExpert && !cur.stationFlight && cities[cur.city].ResearchStation
+			if !charter && !stationFlight {
+				continue
+			}
+			move := Move{Action: CharterFlightAction, Card: card}
+			if !charter {
+				move.Action = StationFlightAction
+			}
+			for i := range cities {
+				if i != cur.city {
+					next(i, spent, move)
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("%v cannot get from %v to %v", player.HumanName, from, to)
+}
@@ -0,0 +1,213 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	autosavePrefix = "autosave_"
+	autosaveSuffix = ".json"
+)
+
+// SavedGame is the on-disk format of a game. Rather than a snapshot of the
+// current state it stores the initial setup and the ordered list of events,
+// so a playthrough can be reconstructed step by step.
+type SavedGame struct {
+	SchemaVersion int             `json:"schema_version"`
+	GameName      string          `json:"game_name"`
+	Setup         json.RawMessage `json:"setup,omitempty"`
+	Snapshot      json.RawMessage `json:"snapshot,omitempty"`
+	Events        []Event         `json:"events"`
+}
+
+func (gs *GameState) SavedGame() (*SavedGame, error) {
+	if gs.Journal == nil {
+		return nil, fmt.Errorf("%v has no journal to save", gs.GameName)
+	}
+	events := gs.Journal.Events
+	if events == nil {
+		events = []Event{}
+	}
+	return &SavedGame{
+		SchemaVersion: SchemaVersion,
+		GameName:      gs.GameName,
+		Setup:         gs.Journal.Setup,
+		Snapshot:      gs.Journal.Snapshot,
+		Events:        events,
+	}, nil
+}
+
+func (gs *GameState) Save(filename string) error {
+	saved, err := gs.SavedGame()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("Could not marshal game as JSON: %v", err)
+	}
+	return writeFileAtomic(filename, data)
+}
+
+// writeFileAtomic writes to a temporary file next to filename and renames
+// it into place, so a crash mid-write never leaves a truncated save behind.
+func writeFileAtomic(filename string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// AutosaveFile names the autosave of a session started at the given time,
+// inside the game's folder.
+func AutosaveFile(gameName string, started time.Time) string {
+	return filepath.Join(gameName, autosavePrefix+started.Format("20060102_150405")+autosaveSuffix)
+}
+
+// LatestAutosave finds the most recently written autosave in a game's
+// folder.
+func LatestAutosave(gameName string) (string, error) {
+	entries, err := ioutil.ReadDir(gameName)
+	if err != nil {
+		return "", fmt.Errorf("No autosaves for %v: %v", gameName, err)
+	}
+	autosaves := []os.FileInfo{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), autosavePrefix) && strings.HasSuffix(entry.Name(), autosaveSuffix) {
+			autosaves = append(autosaves, entry)
+		}
+	}
+	if len(autosaves) == 0 {
+		return "", fmt.Errorf("No autosaves for %v", gameName)
+	}
+	sort.Slice(autosaves, func(i, j int) bool {
+		if autosaves[i].ModTime().Equal(autosaves[j].ModTime()) {
+			return autosaves[i].Name() > autosaves[j].Name()
+		}
+		return autosaves[i].ModTime().After(autosaves[j].ModTime())
+	})
+	return filepath.Join(gameName, autosaves[0].Name()), nil
+}
+
+func (s *SavedGame) journal() *Journal {
+	return &Journal{Setup: s.Setup, Snapshot: s.Snapshot, Events: []Event{}}
+}
+
+// isSavedGame distinguishes the event-sourced save format from the older
+// format, which was a plain JSON snapshot of the GameState.
+func isSavedGame(data []byte) bool {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return false
+	}
+	_, ok := keys["events"]
+	return ok
+}
+
+func loadSavedGame(data []byte) (*GameState, error) {
+	var saved SavedGame
+	err := json.Unmarshal(data, &saved)
+	if err != nil {
+		return nil, err
+	}
+	journal := saved.journal()
+	gs, err := journal.rebuild(saved.GameName, saved.Events)
+	if err != nil {
+		return nil, err
+	}
+	journal.Events = append(journal.Events, saved.Events...)
+	gs.Journal = journal
+	return gs, nil
+}
+
+// Replay steps through a saved game one turn at a time, starting from its
+// initial setup.
+type Replay struct {
+	State  *GameState
+	events []Event
+	next   int
+}
+
+// ReplayStep is a single event applied by the replay along with the
+// outbreak it caused, if any.
+type ReplayStep struct {
+	Event    Event
+	Outbreak *OutbreakResult
+}
+
+func LoadReplay(gameFile string) (*Replay, error) {
+	data, err := ioutil.ReadFile(gameFile)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err = Migrate(data)
+	if err != nil {
+		return nil, fmt.Errorf("Could not migrate %v: %v", gameFile, err)
+	}
+	if !isSavedGame(data) {
+		return nil, fmt.Errorf("%v is a snapshot, only event-sourced saves can be replayed", gameFile)
+	}
+	var saved SavedGame
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		return nil, err
+	}
+	gs, err := saved.journal().rebuild(saved.GameName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Replay{State: gs, events: saved.Events}, nil
+}
+
+func (r *Replay) Done() bool {
+	return r.next >= len(r.events)
+}
+
+func (r *Replay) Remaining() int {
+	return len(r.events) - r.next
+}
+
+// StepTurn applies events until the end of the current turn, that is up to
+// and including the next next-turn event, or until the saved game ends.
+func (r *Replay) StepTurn() ([]ReplayStep, error) {
+	if r.Done() {
+		return nil, fmt.Errorf("Reached the end of the saved game")
+	}
+	steps := []ReplayStep{}
+	for !r.Done() {
+		ev := r.events[r.next]
+		outbreak, err := ev.apply(r.State)
+		if err != nil {
+			return steps, fmt.Errorf("Could not replay event %v (%v): %v", r.next, ev, err)
+		}
+		r.next++
+		steps = append(steps, ReplayStep{ev, outbreak})
+		if ev.Type == NextTurnEvent {
+			break
+		}
+	}
+	return steps, nil
+}
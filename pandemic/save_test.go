@@ -0,0 +1,176 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func playTestTurns(t *testing.T, gs *GameState) {
+	steps := []func() error{
+		func() error { _, err := gs.Infect("atlanta"); return err },
+		func() error { return gs.DrawCard("paris") },
+		func() error { return gs.DrawCard("tokyo") },
+		func() error { _, err := gs.NextTurn(); return err },
+		func() error { _, err := gs.Epidemic("lima"); return err },
+		func() error { gs.SetInfectionRate(3); return nil },
+		func() error { _, err := gs.NextTurn(); return err },
+		func() error { return gs.Quarantine("milan") },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %v: %v", i, err)
+		}
+	}
+}
+
+func TestSaveAndLoadEventSourcedGame(t *testing.T) {
+	gs := newTestGame(t)
+	playTestTurns(t, gs)
+	filename := filepath.Join(t.TempDir(), "game.json")
+	if err := gs.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadGame(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := marshalState(t, gs), marshalState(t, loaded); expected != actual {
+		t.Fatalf("Loaded game differs from saved game:\nexpected %v\nactual   %v", expected, actual)
+	}
+	if len(loaded.Journal.Events) != len(gs.Journal.Events) {
+		t.Fatalf("Expected %v journal events, got %v", len(gs.Journal.Events), len(loaded.Journal.Events))
+	}
+	if _, err := loaded.Undo(); err != nil {
+		t.Fatalf("Should be able to undo into a loaded game's history: %v", err)
+	}
+}
+
+func TestLoadSnapshotGame(t *testing.T) {
+	gs := newTestGame(t)
+	playTestTurns(t, gs)
+	data, err := json.Marshal(gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGame(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.InfectionRate != 3 || loaded.Outbreaks != gs.Outbreaks {
+		t.Fatalf("Snapshot was not restored, infection rate %v", loaded.InfectionRate)
+	}
+	if _, err := LoadReplay(filename); err == nil {
+		t.Fatal("Snapshots should not be replayable")
+	}
+}
+
+func TestReplayStepsTurnByTurn(t *testing.T) {
+	gs := newTestGame(t)
+	playTestTurns(t, gs)
+	filename := filepath.Join(t.TempDir(), "game.json")
+	if err := gs.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := LoadReplay(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSteps := []int{4, 3, 1}
+	for turn, expected := range expectedSteps {
+		steps, err := replay.StepTurn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(steps) != expected {
+			t.Fatalf("Expected %v events in turn %v, got %v", expected, turn, len(steps))
+		}
+	}
+	if !replay.Done() {
+		t.Fatal("Expected the replay to be finished")
+	}
+	if expected, actual := marshalState(t, gs), marshalState(t, replay.State); expected != actual {
+		t.Fatalf("Replayed game differs from played game:\nexpected %v\nactual   %v", expected, actual)
+	}
+	if _, err := replay.StepTurn(); err == nil {
+		t.Fatal("Expected an error stepping past the end of the game")
+	}
+}
+
+func TestSaveLeavesNoTemporaryFiles(t *testing.T) {
+	gs := newTestGame(t)
+	playTestTurns(t, gs)
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "game.json")
+	for i := 0; i < 2; i++ {
+		if err := gs.Save(filename); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "game.json" {
+		t.Fatalf("Expected only game.json in the save folder, got %v entries", len(entries))
+	}
+}
+
+func TestLatestAutosave(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LatestAutosave(dir); err == nil {
+		t.Fatal("Expected an error when there are no autosaves")
+	}
+	gs := newTestGame(t)
+	gs.Infect("atlanta")
+	now := time.Now()
+	older := AutosaveFile(dir, now.Add(-time.Hour))
+	newer := AutosaveFile(dir, now)
+	for _, filename := range []string{newer, older, filepath.Join(dir, "game_manual.json")} {
+		if err := gs.Save(filename); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Chtimes(older, now.Add(-time.Hour), now.Add(-time.Hour))
+	os.Chtimes(newer, now, now)
+
+	latest, err := LatestAutosave(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != newer {
+		t.Fatalf("Expected %v to be the latest autosave, got %v", newer, latest)
+	}
+	resumed, err := LoadGame(latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resumed.Journal.Events) != 1 {
+		t.Fatalf("Expected the resumed game to have 1 event, got %v", len(resumed.Journal.Events))
+	}
+}
+
+func TestJournalRevisionTracksChanges(t *testing.T) {
+	gs := newTestGame(t)
+	if gs.Journal.Revision() != 0 {
+		t.Fatal("A new game should not have any revisions")
+	}
+	gs.Infect("atlanta")
+	gs.Undo()
+	gs.Redo()
+	if revision := gs.Journal.Revision(); revision != 3 {
+		t.Fatalf("Expected 3 revisions, got %v", revision)
+	}
+	if _, err := gs.Infect("xxx"); err == nil || gs.Journal.Revision() != 3 {
+		t.Fatal("A failed command should not change the revision")
+	}
+}
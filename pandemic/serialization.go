@@ -0,0 +1,171 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The game state is a graph: turns point at players, and players and turns
+// share pointers to the cards that were drawn. Encoding it naively copies
+// every shared object, so the decoded game would draw cards into a player
+// that is not part of the player order. Shared objects are therefore
+// written as IDs (player names and card names) and linked back together
+// when decoding. Documents written before this layer existed are upgraded
+// by the schema migrations before they get here.
+
+type turnJSON struct {
+	Player          string            `json:"player"`
+	DrawnCards      []CardName        `json:"drawn_cards"`
+	Outbreaks       []*OutbreakResult `json:"outbreaks,omitempty"`
+	Phase           TurnPhase         `json:"phase,omitempty"`
+	ActionsUsed     int               `json:"actions_used,omitempty"`
+	EpidemicsDrawn  int               `json:"epidemics_drawn,omitempty"`
+	InfectionsDrawn int               `json:"infections_drawn,omitempty"`
+}
+
+func (t Turn) MarshalJSON() ([]byte, error) {
+	tj := turnJSON{
+		DrawnCards:      []CardName{},
+		Outbreaks:       t.Outbreaks,
+		Phase:           t.Phase,
+		ActionsUsed:     t.ActionsUsed,
+		EpidemicsDrawn:  t.EpidemicsDrawn,
+		InfectionsDrawn: t.InfectionsDrawn,
+	}
+	if t.Player != nil {
+		tj.Player = t.Player.HumanName
+	}
+	for _, card := range t.DrawnCards {
+		tj.DrawnCards = append(tj.DrawnCards, card.Name())
+	}
+	return json.Marshal(tj)
+}
+
+// UnmarshalJSON only records the IDs of the turn's player and cards. They
+// are resolved by GameTurns and GameState once the whole graph is decoded.
+func (t *Turn) UnmarshalJSON(data []byte) error {
+	var tj turnJSON
+	if err := json.Unmarshal(data, &tj); err != nil {
+		return err
+	}
+	t.playerID = tj.Player
+	t.drawnIDs = tj.DrawnCards
+	t.Outbreaks = tj.Outbreaks
+	t.Phase = tj.Phase
+	t.ActionsUsed = tj.ActionsUsed
+	t.EpidemicsDrawn = tj.EpidemicsDrawn
+	t.InfectionsDrawn = tj.InfectionsDrawn
+	if t.Phase == "" {
+		// Turns saved before turns had phases.
+		t.Phase = ActionsPhase
+		if len(tj.DrawnCards) >= CityCardsPerTurn {
+			t.Phase = InfectPhase
+		}
+	}
+	return nil
+}
+
+func (t *GameTurns) UnmarshalJSON(data []byte) error {
+	type gameTurns GameTurns
+	if err := json.Unmarshal(data, (*gameTurns)(t)); err != nil {
+		return err
+	}
+	for i, turn := range t.Turns {
+		player, err := t.GetPlayer(turn.playerID)
+		if err != nil {
+			return fmt.Errorf("Turn %v: %v", i, err)
+		}
+		turn.Player = player
+	}
+	return nil
+}
+
+// linkDrawnCards resolves the card IDs of every turn. A drawn card that is
+// still in someone's hand is the very same card as the one in the hand;
+// otherwise a copy is taken from the city deck.
+func (t *GameTurns) linkDrawnCards(deck *CityDeck) error {
+	inHand := map[CardName]*CityCard{}
+	for _, player := range t.PlayerOrder {
+		for _, card := range player.Cards {
+			inHand[card.Name()] = card
+		}
+	}
+	for i, turn := range t.Turns {
+		turn.DrawnCards = []*CityCard{}
+		for _, cardID := range turn.drawnIDs {
+			card, ok := inHand[cardID]
+			if !ok {
+				var err error
+				if card, err = deck.GetCard(cardID); err != nil {
+					return fmt.Errorf("Turn %v: %v", i, err)
+				}
+			}
+			turn.DrawnCards = append(turn.DrawnCards, card)
+		}
+		turn.drawnIDs = nil
+	}
+	return nil
+}
+
+type cityDeckJSON struct {
+	All              []CityCard                `json:"all"`
+	Drawn            []CardName                `json:"drawn"`
+	StartCities      []CardName                `json:"start_cities"`
+	ProbabilityModel *cityDeckProbabilityModel `json:"probability_model"`
+}
+
+func (c CityDeck) MarshalJSON() ([]byte, error) {
+	cj := cityDeckJSON{
+		All:              c.All,
+		Drawn:            []CardName{},
+		StartCities:      []CardName{},
+		ProbabilityModel: c.ProbabilityModel,
+	}
+	for _, card := range c.Drawn {
+		cj.Drawn = append(cj.Drawn, card.Name())
+	}
+	for _, card := range c.StartCities {
+		cj.StartCities = append(cj.StartCities, card.Name())
+	}
+	return json.Marshal(cj)
+}
+
+func (c *CityDeck) UnmarshalJSON(data []byte) error {
+	var cj cityDeckJSON
+	if err := json.Unmarshal(data, &cj); err != nil {
+		return err
+	}
+	c.All = cj.All
+	c.ProbabilityModel = cj.ProbabilityModel
+	var err error
+	if c.Drawn, err = c.resolveCards(cj.Drawn); err != nil {
+		return err
+	}
+	if c.StartCities, err = c.resolveCards(cj.StartCities); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *CityDeck) resolveCards(ids []CardName) ([]CityCard, error) {
+	cards := []CityCard{}
+	for _, cardID := range ids {
+		card, err := c.GetCard(cardID)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, *card)
+	}
+	return cards, nil
+}
+
+func (gs *GameState) UnmarshalJSON(data []byte) error {
+	type gameState GameState
+	if err := json.Unmarshal(data, (*gameState)(gs)); err != nil {
+		return err
+	}
+	if gs.GameTurns == nil || gs.CityDeck == nil {
+		return nil
+	}
+	return gs.GameTurns.linkDrawnCards(gs.CityDeck)
+}
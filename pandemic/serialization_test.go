@@ -0,0 +1,86 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func roundTrip(t *testing.T, gs *GameState) *GameState {
+	data, err := json.Marshal(gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadSnapshot(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+func assertLinked(t *testing.T, gs *GameState) {
+	for i, turn := range gs.GameTurns.Turns {
+		found := false
+		for _, player := range gs.GameTurns.PlayerOrder {
+			found = found || turn.Player == player
+		}
+		if !found {
+			t.Fatalf("Turn %v player %v is not part of the player order", i, turn.Player.HumanName)
+		}
+		for _, card := range turn.DrawnCards {
+			for _, player := range gs.GameTurns.PlayerOrder {
+				for _, held := range player.Cards {
+					if held.Name() == card.Name() && held != card {
+						t.Fatalf("Turn %v drew %v but %v holds a different copy", i, card.Name(), player.HumanName)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRoundTripLinksTurnsPlayersAndCards(t *testing.T) {
+	gs := newTestGame(t)
+	playTestTurns(t, gs)
+	loaded := roundTrip(t, gs)
+	assertLinked(t, loaded)
+
+	cur, err := loaded.GameTurns.CurrentTurn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur.Player != loaded.GameTurns.PlayerOrder[0] {
+		t.Fatal("Current turn should point at the first player in the order")
+	}
+	if err := loaded.DrawCard("essen"); err != nil {
+		t.Fatal(err)
+	}
+	if held := loaded.GameTurns.PlayerOrder[0].Cards; held[len(held)-1].Name() != "essen" {
+		t.Fatalf("Drawn card should have gone to the current player, they hold %v", held)
+	}
+}
+
+func TestRoundTripPlaysLikeUnsavedGame(t *testing.T) {
+	unsaved := newTestGame(t)
+	playTestTurns(t, unsaved)
+	loaded := roundTrip(t, unsaved)
+
+	for _, gs := range []*GameState{unsaved, loaded} {
+		if err := gs.DrawCard("essen"); err != nil {
+			t.Fatal(err)
+		}
+		order := gs.GameTurns.PlayerOrder
+		if err := gs.ExchangeCard(order[0], order[1], "essen"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gs.NextTurn(); err != nil {
+			t.Fatal(err)
+		}
+		if err := gs.DrawCard("madrid"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertLinked(t, loaded)
+	if expected, actual := marshalState(t, unsaved), marshalState(t, loaded); expected != actual {
+		t.Fatalf("Loaded game diverged:\nexpected %v\nactual   %v", expected, actual)
+	}
+}
@@ -0,0 +1,283 @@
+// Package sim plays a game forward many times with random cards, to
+// estimate the risks of the coming turns beyond what the exact odds of the
+// next infections can tell, such as chains of outbreaks.
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/gmsconstantino/pandemic-nerd-hurd/pandemic"
+)
+
+// Options say how far and how often to play the game forward.
+type Options struct {
+	Turns      int
+	Iterations int
+	Seed       int64
+}
+
+// Distribution holds one value for every simulated game.
+type Distribution []int
+
+func (d Distribution) Mean() float64 {
+	if len(d) == 0 {
+		return 0.0
+	}
+	var total int
+	for _, v := range d {
+		total += v
+	}
+	return float64(total) / float64(len(d))
+}
+
+// Percentile is the value that the given fraction of the games do not
+// exceed.
+func (d Distribution) Percentile(p float64) int {
+	if len(d) == 0 {
+		return 0
+	}
+	sorted := append(Distribution{}, d...)
+	sort.Ints(sorted)
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+func (d Distribution) Max() int {
+	return d.Percentile(1.0)
+}
+
+// AtLeast is the fraction of the games with a value of at least v.
+func (d Distribution) AtLeast(v int) float64 {
+	if len(d) == 0 {
+		return 0.0
+	}
+	var count int
+	for _, value := range d {
+		if value >= v {
+			count++
+		}
+	}
+	return float64(count) / float64(len(d))
+}
+
+// Result sums up the simulated games: how many were lost and why, the
+// outbreaks and the cubes of each disease placed in every game, and the
+// number of games in which each city outbroke.
+type Result struct {
+	Options
+	Losses        int
+	LossReasons   map[string]int
+	Outbreaks     Distribution
+	Cubes         map[pandemic.DiseaseType]Distribution
+	CityOutbreaks map[pandemic.CityName]int
+}
+
+func (r *Result) LossProbability() float64 {
+	return float64(r.Losses) / float64(r.Iterations)
+}
+
+// OutbreakRisk is the fraction of the games in which the city outbroke.
+func (r *Result) OutbreakRisk(cn pandemic.CityName) float64 {
+	return float64(r.CityOutbreaks[cn]) / float64(r.Iterations)
+}
+
+// RiskiestCities lists the cities that outbroke in any game, the most
+// likely first.
+func (r *Result) RiskiestCities() []pandemic.CityName {
+	cities := []pandemic.CityName{}
+	for cn := range r.CityOutbreaks {
+		cities = append(cities, cn)
+	}
+	sort.Slice(cities, func(i, j int) bool {
+		if r.CityOutbreaks[cities[i]] != r.CityOutbreaks[cities[j]] {
+			return r.CityOutbreaks[cities[i]] > r.CityOutbreaks[cities[j]]
+		}
+		return cities[i] < cities[j]
+	})
+	return cities
+}
+
+// Diseases lists the diseases of the cube distributions in a stable order.
+func (r *Result) Diseases() []pandemic.DiseaseType {
+	diseases := []pandemic.DiseaseType{}
+	for dt := range r.Cubes {
+		diseases = append(diseases, dt)
+	}
+	sort.Slice(diseases, func(i, j int) bool { return diseases[i] < diseases[j] })
+	return diseases
+}
+
+// Run plays copies of the game forward from the coming city cards. Every
+// turn draws two city cards and then infects at the infection rate, with
+// epidemics as likely as the city deck's striation scenarios allow and the
+// infection cards drawn at random from the top striation. The players take
+// no actions, so nobody treats, cures or quarantines along the way. A game
+// stops when it is lost.
+func Run(gs *pandemic.GameState, opts Options) (*Result, error) {
+	if opts.Iterations < 1 {
+		return nil, fmt.Errorf("Must simulate at least one game, not %v", opts.Iterations)
+	}
+	if opts.Turns < 1 {
+		return nil, fmt.Errorf("Must simulate at least one turn, not %v", opts.Turns)
+	}
+	result := &Result{
+		Options:       opts,
+		LossReasons:   map[string]int{},
+		Cubes:         map[pandemic.DiseaseType]Distribution{},
+		CityOutbreaks: map[pandemic.CityName]int{},
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	for i := 0; i < opts.Iterations; i++ {
+		clone, err := gs.Clone()
+		if err != nil {
+			return nil, err
+		}
+		game := newGame(clone, rng)
+		reason, err := game.play(opts.Turns)
+		if err != nil {
+			return nil, fmt.Errorf("Game %v: %v", i, err)
+		}
+		if reason != "" {
+			result.Losses++
+			result.LossReasons[reason]++
+		}
+		result.Outbreaks = append(result.Outbreaks, game.gs.Outbreaks-gs.Outbreaks)
+		for _, data := range gs.DiseaseData {
+			result.Cubes[data.Type] = append(result.Cubes[data.Type], game.cubesPlaced(data.Type))
+		}
+		for cn := range game.outbroke {
+			result.CityOutbreaks[cn]++
+		}
+	}
+	return result, nil
+}
+
+// game is one copy of the game being played forward.
+type game struct {
+	gs       *pandemic.GameState
+	rng      *rand.Rand
+	cubes    map[pandemic.DiseaseType]int
+	outbroke map[pandemic.CityName]bool
+}
+
+func newGame(gs *pandemic.GameState, rng *rand.Rand) *game {
+	gs.IgnoreRules = true
+	if gs.DiseaseSupply == nil {
+		gs.DiseaseSupply = pandemic.NewDiseaseSupply(gs.DiseaseData, gs.Cities)
+	}
+	g := &game{gs: gs, rng: rng, outbroke: map[pandemic.CityName]bool{}}
+	g.cubes = g.cubesOnBoard()
+	return g
+}
+
+func (g *game) cubesOnBoard() map[pandemic.DiseaseType]int {
+	cubes := map[pandemic.DiseaseType]int{}
+	for _, city := range *g.gs.Cities {
+		for dt, count := range city.Cubes {
+			cubes[dt] += count
+		}
+	}
+	return cubes
+}
+
+func (g *game) cubesPlaced(dt pandemic.DiseaseType) int {
+	return g.cubesOnBoard()[dt] - g.cubes[dt]
+}
+
+// play draws the cards of the turns, returning why the game was lost, if
+// it was.
+func (g *game) play(turns int) (string, error) {
+	for turn := 0; turn < turns; turn++ {
+		for draw := 0; draw < pandemic.CityCardsPerTurn; draw++ {
+			if g.gs.CityDeck.RemainingCards() == 0 {
+				return "city cards", nil
+			}
+			if err := g.drawCityCard(); err != nil {
+				return "", err
+			}
+			if reason := g.lost(); reason != "" {
+				return reason, nil
+			}
+		}
+		for infection := 0; infection < g.gs.InfectionRate; infection++ {
+			if len(g.gs.InfectionDeck.Striations) == 0 {
+				break
+			}
+			cards := g.gs.InfectionDeck.TopStriation().Members()
+			result, err := g.gs.Infect(pandemic.CityName(cards[g.rng.Intn(len(cards))]))
+			if err != nil {
+				return "", err
+			}
+			g.record(result)
+			if reason := g.lost(); reason != "" {
+				return reason, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// drawCityCard draws an epidemic as often as the city deck's probability
+// model expects one, or else any of the cards left.
+func (g *game) drawCityCard() error {
+	deck := g.gs.CityDeck
+	epidemicsLeft := deck.NumEpidemics() - deck.EpidemicsDrawn()
+	cards := g.remainingCards()
+	if epidemicsLeft > 0 && (len(cards) == 0 || g.rng.Float64() < deck.EpidemicAnalysis().FirstCardProbability) {
+		bottom := g.gs.InfectionDeck.BottomStriation().Members()
+		if len(bottom) == 0 {
+			return fmt.Errorf("No infection cards left to pull for an epidemic")
+		}
+		rate := g.gs.InfectionRateAfter(1)
+		result, err := g.gs.Epidemic(pandemic.CityName(bottom[g.rng.Intn(len(bottom))]))
+		if err != nil {
+			return err
+		}
+		g.gs.SetInfectionRate(rate)
+		g.record(result)
+		return nil
+	}
+	_, err := deck.DrawCard(cards[g.rng.Intn(len(cards))])
+	return err
+}
+
+// remainingCards lists the cards left in the city deck that are not
+// epidemics.
+func (g *game) remainingCards() []pandemic.CardName {
+	drawn := map[pandemic.CardName]bool{}
+	for _, card := range g.gs.CityDeck.Drawn {
+		drawn[card.Name()] = true
+	}
+	cards := []pandemic.CardName{}
+	for _, card := range g.gs.CityDeck.All {
+		if !card.IsEpidemic && !drawn[card.Name()] {
+			cards = append(cards, card.Name())
+		}
+	}
+	return cards
+}
+
+func (g *game) record(result *pandemic.OutbreakResult) {
+	if result == nil {
+		return
+	}
+	g.outbroke[result.Origin] = true
+	for _, cn := range result.Chained {
+		g.outbroke[cn] = true
+	}
+}
+
+// lost names what lost the game, too many outbreaks or running out of the
+// cubes of a disease.
+func (g *game) lost() string {
+	if g.gs.Outbreaks >= pandemic.MaxOutbreaks {
+		return "outbreaks"
+	}
+	for _, dt := range g.gs.DiseaseSupply.Diseases() {
+		if g.gs.DiseaseSupply[dt] < 0 {
+			return fmt.Sprintf("%v cubes", dt)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package sim
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gmsconstantino/pandemic-nerd-hurd/pandemic"
+)
+
+func newTestGame(t *testing.T) *pandemic.GameState {
+	gs, err := pandemic.NewGame("../../data/pandemicboard.json", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gs
+}
+
+func TestRunIsRepeatable(t *testing.T) {
+	gs := newTestGame(t)
+	before, _ := json.Marshal(gs)
+	opts := Options{Turns: 3, Iterations: 50, Seed: 7}
+	first, err := Run(gs, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Run(gs, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("The same seed should simulate the same games")
+	}
+	if after, _ := json.Marshal(gs); string(before) != string(after) {
+		t.Fatal("Simulating should not change the game")
+	}
+	if len(first.Outbreaks) != opts.Iterations {
+		t.Fatalf("Expected the outbreaks of %v games, got %v", opts.Iterations, len(first.Outbreaks))
+	}
+	for i := range first.Outbreaks {
+		var cubes int
+		for _, dt := range first.Diseases() {
+			cubes += first.Cubes[dt][i]
+		}
+		// 3 turns infect at least 6 times, or 3 cubes and an outbreak for
+		// the epidemic city drawn again.
+		if cubes < 6 && first.Outbreaks[i] == 0 {
+			t.Fatalf("Game %v placed only %v cubes", i, cubes)
+		}
+	}
+}
+
+func TestRunCountsLosses(t *testing.T) {
+	gs := newTestGame(t)
+	// enough cubes in the box that only the outbreaks can lose the game
+	for i := range gs.DiseaseData {
+		gs.DiseaseData[i].Cubes = 1000
+	}
+	gs.DiseaseSupply = nil
+	for _, cn := range gs.Cities.CityNames() {
+		if err := gs.SetInfections(cn, "", 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gs.Outbreaks = pandemic.MaxOutbreaks - 1
+	result, err := Run(gs, Options{Turns: 1, Iterations: 20, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.LossProbability() != 1.0 || result.LossReasons["outbreaks"] != 20 {
+		t.Fatalf("Every game should be lost to the next outbreak, got %v", result.LossReasons)
+	}
+	if len(result.RiskiestCities()) == 0 || result.Outbreaks.Percentile(0.5) < 1 {
+		t.Fatal("Expected the outbreaks to be counted")
+	}
+	risk := 0.0
+	for _, cn := range result.RiskiestCities() {
+		risk += result.OutbreakRisk(cn)
+	}
+	if risk < 1.0 {
+		t.Fatalf("Every game should have an outbreaking city, got %v", risk)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	d := Distribution{3, 0, 1, 1, 5}
+	if d.Mean() != 2.0 || d.Percentile(0.5) != 1 || d.Max() != 5 || d.AtLeast(1) != 0.8 {
+		t.Fatalf("Unexpected statistics of %v", d)
+	}
+	if _, err := Run(newTestGame(t), Options{Turns: 1}); err == nil {
+		t.Fatal("Expected an error without iterations")
+	}
+}
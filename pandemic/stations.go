@@ -0,0 +1,113 @@
+package pandemic
+
+import "fmt"
+
+// ResearchStationsPerGame is the number of research stations in the box.
+const ResearchStationsPerGame = 6
+
+// StationSupply is the number of research stations the game is played with.
+func (gs *GameState) StationSupply() int {
+	if gs.StationLimit > 0 {
+		return gs.StationLimit
+	}
+	return ResearchStationsPerGame
+}
+
+// StationsLeft is the number of research stations that are not on the
+// board.
+func (gs *GameState) StationsLeft() int {
+	return gs.StationSupply() - len(gs.Cities.WithStation())
+}
+
+// WithStation lists the cities with a research station.
+func (c Cities) WithStation() []CityName {
+	names := []CityName{}
+	for _, city := range c {
+		if city.ResearchStation {
+			names = append(names, city.Name)
+		}
+	}
+	return names
+}
+
+// checkNewStation checks that a research station can be placed in the
+// city: there is one left in the supply and the city is not panicking too
+// much to build one.
+func (gs *GameState) checkNewStation(city *City) error {
+	if city.ResearchStation {
+		return fmt.Errorf("%v already has a research station", city.Name)
+	}
+	if gs.IgnoreRules {
+		return nil
+	}
+	if !city.PanicLevel.CanBuildResearchStations() {
+		return fmt.Errorf("Research stations cannot be built in %v while it is %v", city.Name, city.PanicLevel)
+	}
+	if gs.StationsLeft() <= 0 {
+		return fmt.Errorf("All %v research stations are on the board, remove one first", gs.StationSupply())
+	}
+	return nil
+}
+
+// PlaceStation puts a research station in a city without taking an action,
+// to set up or correct the board.
+func (gs *GameState) PlaceStation(cn CityName) error {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if err := gs.checkNewStation(city); err != nil {
+		return err
+	}
+	city.ResearchStation = true
+	gs.record(Event{Type: PlaceStationEvent, City: cn})
+	return nil
+}
+
+// RemoveStation returns the research station of a city to the supply.
+func (gs *GameState) RemoveStation(cn CityName) error {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if !city.ResearchStation {
+		return fmt.Errorf("%v has no research station", cn)
+	}
+	city.ResearchStation = false
+	gs.record(Event{Type: RemoveStationEvent, City: cn})
+	return nil
+}
+
+// loseStation removes the research station of a city that is now panicking
+// too much to keep one, returning true if it did.
+func (c *City) loseStation() bool {
+	if !c.ResearchStation || c.PanicLevel.CanBuildResearchStations() {
+		return false
+	}
+	c.ResearchStation = false
+	return true
+}
+
+// validateStations checks that the new game does not place more research
+// stations than the supply holds.
+func (n *NewGameSettings) validateStations() error {
+	supply := ResearchStationsPerGame
+	if n.StationLimit > 0 {
+		supply = n.StationLimit
+	}
+	if placed := len(n.Cities.WithStation()); placed > supply {
+		return fmt.Errorf("The game places %v research stations, but there are only %v", placed, supply)
+	}
+	return nil
+}
+
+// placeStartStation puts a research station in the city the players start
+// in, unless the game file already placed stations.
+func placeStartStation(cities Cities, players []*Player) {
+	if len(cities.WithStation()) > 0 || len(players) == 0 {
+		return
+	}
+	if city, err := cities.GetCity(players[0].Location); err == nil {
+		city.ResearchStation = true
+	}
+}
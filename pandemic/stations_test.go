@@ -0,0 +1,86 @@
+package pandemic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStationSupply(t *testing.T) {
+	gs := newTestGame(t)
+	if !reflect.DeepEqual(gs.Cities.WithStation(), []CityName{"atlanta"}) || gs.StationsLeft() != 5 {
+		t.Fatalf("The game should start with a station where the players start, got %v", gs.Cities.WithStation())
+	}
+	for _, cn := range []CityName{"paris", "tokyo", "lima", "cairo", "delhi"} {
+		if err := gs.PlaceStation(cn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gs.PlaceStation("london"); err == nil {
+		t.Fatal("Placing a seventh station should be rejected")
+	}
+	if err := gs.RemoveStation("paris"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.PlaceStation("london"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := roundTrip(t, gs)
+	if !reflect.DeepEqual(loaded.Cities.WithStation(), gs.Cities.WithStation()) {
+		t.Fatalf("Stations should survive saving and loading, got %v", loaded.Cities.WithStation())
+	}
+	if _, err := gs.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if london, _ := gs.GetCity("london"); london.ResearchStation {
+		t.Fatal("Undo should remove the station again")
+	}
+}
+
+func TestStationsFromSettings(t *testing.T) {
+	settings := boardSettings(t)
+	settings.StationLimit = 1
+	for _, city := range settings.Cities[:2] {
+		city.ResearchStation = true
+	}
+	if _, err := NewGameFromSettings(settings, "test"); err == nil {
+		t.Fatal("Placing more stations than the supply should be rejected")
+	}
+	settings.Cities[1].ResearchStation = false
+	gs, err := NewGameFromSettings(settings, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gs.Cities.WithStation()) != 1 || gs.StationsLeft() != 0 {
+		t.Fatalf("The file's stations should replace the start station, got %v", gs.Cities.WithStation())
+	}
+}
+
+func TestRiotingCitiesLoseStations(t *testing.T) {
+	gs := newTestGame(t)
+	if err := gs.SetPanicLevel("atlanta", Unstable); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfections("atlanta", "", 3); err != nil {
+		t.Fatal(err)
+	}
+	result, err := gs.Infect("atlanta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atlanta, _ := gs.GetCity("atlanta"); atlanta.ResearchStation || !reflect.DeepEqual(result.StationsLost, []CityName{"atlanta"}) {
+		t.Fatal("An outbreak that makes a city riot should remove its station")
+	}
+	if err := gs.PlaceStation("atlanta"); err == nil {
+		t.Fatal("Stations cannot be placed in rioting cities")
+	}
+	if err := gs.PlaceStation("paris"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetPanicLevel("paris", Rioting3); err != nil {
+		t.Fatal(err)
+	}
+	if paris, _ := gs.GetCity("paris"); paris.ResearchStation {
+		t.Fatal("Raising the panic level to rioting should remove the station")
+	}
+}
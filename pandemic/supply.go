@@ -0,0 +1,111 @@
+package pandemic
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// CubesPerDisease is the number of cubes of each colour in the base game.
+	CubesPerDisease = 24
+	// MaxOutbreaks is the outbreak that loses the game.
+	MaxOutbreaks = 8
+)
+
+// DiseaseSupply counts the cubes of each disease that are not on the board.
+// A negative count means more cubes were needed than the box holds, which
+// loses the game.
+type DiseaseSupply map[DiseaseType]int
+
+// NewDiseaseSupply fills the supply for every disease and takes out the
+// cubes already placed on the cities.
+func NewDiseaseSupply(diseases Diseases, cities *Cities) DiseaseSupply {
+	supply := DiseaseSupply{}
+	for _, data := range diseases {
+		supply[data.Type] = data.TotalCubes()
+	}
+	for _, city := range *cities {
+		for dt, cubes := range city.Cubes {
+			supply[dt] -= cubes
+		}
+	}
+	return supply
+}
+
+// take moves cubes from the supply onto the board. A negative count
+// returns them to the supply.
+func (s DiseaseSupply) take(data DiseaseData, cubes int) {
+	if _, ok := s[data.Type]; !ok {
+		s[data.Type] = data.TotalCubes()
+	}
+	s[data.Type] -= cubes
+}
+
+// Diseases lists the diseases in the supply in a stable order.
+func (s DiseaseSupply) Diseases() []DiseaseType {
+	diseases := []DiseaseType{}
+	for dt := range s {
+		diseases = append(diseases, dt)
+	}
+	sort.Slice(diseases, func(i, j int) bool { return diseases[i] < diseases[j] })
+	return diseases
+}
+
+// placeCubes keeps the supply in step with a change to the cubes of a
+// disease in a city. before is the number of cubes of the disease the city
+// had before the change.
+func (gs *GameState) placeCubes(city *City, dt DiseaseType, before int) {
+	if gs.DiseaseSupply == nil {
+		gs.DiseaseSupply = NewDiseaseSupply(gs.DiseaseData, gs.Cities)
+		return
+	}
+	gs.DiseaseSupply.take(gs.DataForDisease(dt), city.CubesOf(dt)-before)
+}
+
+// cardsToDraw is the number of city cards the current player still has to
+// draw this turn.
+func (gs *GameState) cardsToDraw() int {
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		return 0
+	}
+	return CityCardsPerTurn - curTurn.CardsDrawn()
+}
+
+// LossConditions lists the reasons the game is lost: too many outbreaks,
+// needing more cubes of a disease than there are, or not having enough
+// city cards left for the current player to draw.
+func (gs *GameState) LossConditions() []string {
+	reasons := []string{}
+	if gs.Outbreaks >= MaxOutbreaks {
+		reasons = append(reasons, fmt.Sprintf("%v outbreaks", gs.Outbreaks))
+	}
+	for _, dt := range gs.DiseaseSupply.Diseases() {
+		if gs.DiseaseSupply[dt] < 0 {
+			reasons = append(reasons, fmt.Sprintf("Ran out of %v cubes", dt))
+		}
+	}
+	if gs.IsStarted && gs.CityDeck.RemainingCards() < gs.cardsToDraw() {
+		reasons = append(reasons, "Ran out of city cards")
+	}
+	return reasons
+}
+
+// LossWarnings lists the ways the game could be lost on the next infection
+// or the next turn.
+func (gs *GameState) LossWarnings() []string {
+	warnings := []string{}
+	if gs.Outbreaks == MaxOutbreaks-1 {
+		warnings = append(warnings, "One more outbreak loses the game")
+	}
+	for _, dt := range gs.DiseaseSupply.Diseases() {
+		if remaining := gs.DiseaseSupply[dt]; remaining >= 0 && remaining <= 3 {
+			warnings = append(warnings, fmt.Sprintf("Only %v %v cubes left", remaining, dt))
+		}
+	}
+	remaining := gs.CityDeck.RemainingCards()
+	if gs.IsStarted && remaining >= gs.cardsToDraw() && remaining < gs.cardsToDraw()+CityCardsPerTurn {
+		warnings = append(warnings, "This is the last turn with city cards")
+	}
+	return warnings
+}
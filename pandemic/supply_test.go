@@ -0,0 +1,95 @@
+package pandemic
+
+import "testing"
+
+func TestDiseaseSupplyFollowsCubes(t *testing.T) {
+	gs := newTestGame(t)
+	for _, dt := range gs.DiseaseSupply.Diseases() {
+		if gs.DiseaseSupply[dt] != CubesPerDisease {
+			t.Fatalf("Expected a full supply of %v, got %v", dt, gs.DiseaseSupply[dt])
+		}
+	}
+	for _, cn := range []CityName{"atlanta", "chicago"} {
+		if err := gs.SetInfections(cn, "", 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := gs.Infect("atlanta"); err != nil {
+		t.Fatal(err)
+	}
+	// atlanta and chicago hold 3 each, and the outbreaks put one blue
+	// cube on each of montreal, mexicocity, losangeles, sanfrancisco,
+	// washington and miami, even though three of them are yellow.
+	if blue := gs.DiseaseSupply[Blue.Type]; blue != CubesPerDisease-12 {
+		t.Fatalf("Expected %v blue cubes left, got %v", CubesPerDisease-12, blue)
+	}
+	if yellow := gs.DiseaseSupply[Yellow.Type]; yellow != CubesPerDisease {
+		t.Fatalf("Expected %v yellow cubes left, got %v", CubesPerDisease, yellow)
+	}
+
+	if err := gs.TreatInfections("atlanta", "", 2); err != nil {
+		t.Fatal(err)
+	}
+	if blue := gs.DiseaseSupply[Blue.Type]; blue != CubesPerDisease-10 {
+		t.Fatalf("Treating should return cubes to the supply, got %v", blue)
+	}
+	if _, err := gs.Epidemic("paris"); err != nil {
+		t.Fatal(err)
+	}
+	if blue := gs.DiseaseSupply[Blue.Type]; blue != CubesPerDisease-13 {
+		t.Fatalf("An epidemic should take 3 cubes, got %v", blue)
+	}
+
+	if _, err := gs.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if blue := gs.DiseaseSupply[Blue.Type]; blue != CubesPerDisease-10 {
+		t.Fatalf("Undo should restore the supply, got %v", blue)
+	}
+	fresh := NewDiseaseSupply(gs.DiseaseData, gs.Cities)
+	for _, dt := range fresh.Diseases() {
+		if fresh[dt] != gs.DiseaseSupply[dt] {
+			t.Fatalf("Supply of %v drifted from the board: %v vs %v", dt, gs.DiseaseSupply[dt], fresh[dt])
+		}
+	}
+}
+
+func TestLossConditions(t *testing.T) {
+	gs := newTestGame(t)
+	if len(gs.LossConditions()) != 0 {
+		t.Fatalf("A new game should not be lost: %v", gs.LossConditions())
+	}
+	for _, cn := range []CityName{"sanfrancisco", "washington", "atlanta", "montreal", "chicago", "newyork", "london", "essen"} {
+		if err := gs.SetInfections(cn, "", 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(gs.LossConditions()) != 0 || len(gs.LossWarnings()) != 1 {
+		t.Fatalf("Using every blue cube should only warn, got %v %v", gs.LossConditions(), gs.LossWarnings())
+	}
+	if err := gs.SetInfections("paris", "", 1); err != nil {
+		t.Fatal(err)
+	}
+	if lost := gs.LossConditions(); len(lost) != 1 || lost[0] != "Ran out of Blue cubes" {
+		t.Fatalf("Expected to run out of blue cubes, got %v", lost)
+	}
+
+	gs = newTestGame(t)
+	gs.Outbreaks = MaxOutbreaks - 1
+	if len(gs.LossWarnings()) != 1 {
+		t.Fatal("Expected a warning one outbreak from losing")
+	}
+	gs.Outbreaks = MaxOutbreaks
+	if len(gs.LossConditions()) != 1 {
+		t.Fatal("Expected the game to be lost at the eighth outbreak")
+	}
+
+	gs = newTestGame(t)
+	gs.StartGame()
+	for len(gs.CityDeck.Drawn) < gs.CityDeck.Total()-1 {
+		gs.CityDeck.Drawn = append(gs.CityDeck.Drawn, gs.CityDeck.All[len(gs.CityDeck.Drawn)])
+	}
+	if lost := gs.LossConditions(); len(lost) != 1 || lost[0] != "Ran out of city cards" {
+		t.Fatalf("Expected to run out of city cards with one left, got %v", lost)
+	}
+}
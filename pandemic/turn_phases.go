@@ -0,0 +1,150 @@
+package pandemic
+
+import "fmt"
+
+// TurnPhase is the step of a turn the current player is in. A turn takes
+// its actions, draws 2 city cards and then draws as many infection cards as
+// the infection rate.
+type TurnPhase string
+
+const (
+	ActionsPhase TurnPhase = "actions"
+	DrawPhase    TurnPhase = "draw"
+	InfectPhase  TurnPhase = "infect"
+	DonePhase    TurnPhase = "done"
+)
+
+var turnPhases = []TurnPhase{ActionsPhase, DrawPhase, InfectPhase, DonePhase}
+
+func (p TurnPhase) index() int {
+	for i, phase := range turnPhases {
+		if phase == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// CardsDrawn counts the city cards and epidemics drawn this turn.
+func (t *Turn) CardsDrawn() int {
+	return len(t.DrawnCards) + t.EpidemicsDrawn
+}
+
+// advanceTo moves the turn on to the phase. Turns never go back to an
+// earlier phase.
+func (t *Turn) advanceTo(phase TurnPhase) {
+	if phase.index() > t.Phase.index() {
+		t.Phase = phase
+	}
+}
+
+// checksRules is true once the game has started, unless IgnoreRules is set
+// to correct mistakes or replay saved games.
+func (gs *GameState) checksRules() bool {
+	return gs.IsStarted && !gs.IgnoreRules
+}
+
+// correcting is true while the rules are ignored to correct a mistake, or
+// while replaying an event that did.
+func (gs *GameState) correcting() bool {
+	if gs.replaying != nil {
+		return gs.replaying.Correction
+	}
+	return gs.IgnoreRules
+}
+
+// requirePhase rejects commands issued outside of the given phases.
+func (gs *GameState) requirePhase(what string, phases ...TurnPhase) error {
+	if !gs.checksRules() {
+		return nil
+	}
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		return err
+	}
+	for _, phase := range phases {
+		if curTurn.Phase == phase {
+			return nil
+		}
+	}
+	return fmt.Errorf("Cannot %v in the %v phase. %v", what, curTurn.Phase, gs.PhaseStatus())
+}
+
+// useAction counts an action of the current player and moves on to drawing
+// once they are all used. Corrections take no action.
+func (gs *GameState) useAction() {
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil || !gs.IsStarted || gs.correcting() {
+		return
+	}
+	curTurn.ActionsUsed++
+	if curTurn.ActionsUsed >= curTurn.Player.Abilities().ActionsPerTurn() {
+		curTurn.advanceTo(DrawPhase)
+	}
+}
+
+// cardDrawn moves the turn on to drawing, and to infecting once both city
+// cards are drawn.
+func (gs *GameState) cardDrawn(curTurn *Turn) {
+	if !gs.IsStarted {
+		return
+	}
+	curTurn.advanceTo(DrawPhase)
+	if curTurn.CardsDrawn() >= CityCardsPerTurn {
+		curTurn.advanceTo(InfectPhase)
+	}
+}
+
+// infectionDrawn counts an infection card of the current turn and ends the
+// turn once the infection rate is reached.
+func (gs *GameState) infectionDrawn() {
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil || !gs.IsStarted {
+		return
+	}
+	curTurn.InfectionsDrawn++
+	curTurn.advanceTo(InfectPhase)
+	if curTurn.InfectionsDrawn >= gs.InfectionRate {
+		curTurn.advanceTo(DonePhase)
+	}
+}
+
+// SetPhase moves the current turn on to a later phase, for example to end
+// the actions early or to skip infections after One Quiet Night. Going back
+// to an earlier phase needs IgnoreRules.
+func (gs *GameState) SetPhase(phase TurnPhase) error {
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		return err
+	}
+	if phase.index() < 0 {
+		return fmt.Errorf("%v is not a turn phase", phase)
+	}
+	if phase.index() < curTurn.Phase.index() && !gs.IgnoreRules {
+		return fmt.Errorf("Cannot go back from the %v phase to the %v phase", curTurn.Phase, phase)
+	}
+	curTurn.Phase = phase
+	gs.record(Event{Type: PhaseEvent, Phase: phase})
+	return nil
+}
+
+// PhaseStatus describes what the current player still has to do this turn.
+func (gs *GameState) PhaseStatus() string {
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		return err.Error()
+	}
+	if !gs.IsStarted {
+		return "The game has not started yet"
+	}
+	switch curTurn.Phase {
+	case ActionsPhase:
+		return fmt.Sprintf("%v has %v of %v actions left", curTurn.Player.HumanName, gs.ActionsRemaining(), curTurn.Player.Abilities().ActionsPerTurn())
+	case DrawPhase:
+		return fmt.Sprintf("%v has to draw %v more city cards", curTurn.Player.HumanName, CityCardsPerTurn-curTurn.CardsDrawn())
+	case InfectPhase:
+		return fmt.Sprintf("%v has to draw %v more infection cards", curTurn.Player.HumanName, gs.InfectionRate-curTurn.InfectionsDrawn)
+	default:
+		return fmt.Sprintf("%v's turn is over, move on with next-turn", curTurn.Player.HumanName)
+	}
+}
@@ -0,0 +1,124 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTurnPhases(t *testing.T) {
+	gs := newTestGame(t)
+	for _, cn := range []CityName{"paris", "tokyo"} {
+		if _, err := gs.Infect(cn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gs.StartGame()
+	player := gs.GameTurns.PlayerOrder[0]
+	if _, err := gs.Infect("paris"); err == nil {
+		t.Fatal("Infecting should be rejected before drawing city cards")
+	}
+	if _, err := gs.NextTurn(); err == nil {
+		t.Fatal("The turn should not end during the actions")
+	}
+	for _, cn := range []CityName{"chicago", "atlanta"} {
+		if err := gs.MovePlayer(player, cn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cur, _ := gs.GameTurns.CurrentTurn()
+	if cur.Phase != ActionsPhase || cur.ActionsUsed != 2 {
+		t.Fatalf("Expected 2 actions used, got %v in the %v phase", cur.ActionsUsed, cur.Phase)
+	}
+
+	if err := gs.DrawCard("madrid"); err != nil {
+		t.Fatal(err)
+	}
+	if cur.Phase != DrawPhase {
+		t.Fatalf("Drawing should end the actions, got %v", cur.Phase)
+	}
+	if err := gs.MovePlayer(player, "chicago"); err == nil {
+		t.Fatal("Moving should be rejected after drawing")
+	}
+	if _, err := gs.Epidemic("lima"); err != nil {
+		t.Fatal(err)
+	}
+	if cur.Phase != InfectPhase || cur.CardsDrawn() != 2 {
+		t.Fatalf("An epidemic should count as a drawn card, got %v cards in the %v phase", cur.CardsDrawn(), cur.Phase)
+	}
+	if err := gs.DrawCard("tokyo"); err == nil {
+		t.Fatal("A third card should be rejected")
+	}
+	for _, cn := range []CityName{"paris", "tokyo"} {
+		if _, err := gs.Infect(cn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cur.Phase != DonePhase || cur.InfectionsDrawn != 2 {
+		t.Fatalf("Expected the turn to be done after 2 infections, got %v", cur.Phase)
+	}
+	if _, err := gs.Infect("lima"); err == nil {
+		t.Fatal("Infecting should be rejected once the infection rate is reached")
+	}
+
+	loaded := roundTrip(t, gs)
+	if loadedTurn, _ := loaded.GameTurns.CurrentTurn(); loadedTurn.Phase != DonePhase || loadedTurn.ActionsUsed != 2 || loadedTurn.EpidemicsDrawn != 1 {
+		t.Fatalf("The phase and counters should survive saving and loading, got %+v", loadedTurn)
+	}
+	if _, err := gs.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := gs.GameTurns.CurrentTurn(); cur.Phase != InfectPhase || cur.InfectionsDrawn != 1 {
+		t.Fatalf("Undo should restore the phase, got %v with %v infections", cur.Phase, cur.InfectionsDrawn)
+	}
+	if _, _, err := gs.Redo(); err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := gs.NextTurn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Phase != ActionsPhase || next.Player == player {
+		t.Fatal("The next player should start with their actions")
+	}
+}
+
+func TestSetPhaseAndOverride(t *testing.T) {
+	gs := newTestGame(t)
+	gs.StartGame()
+	if err := gs.SetPhase(InfectPhase); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetPhase(ActionsPhase); err == nil {
+		t.Fatal("Going back to an earlier phase should need an override")
+	}
+	if err := gs.SetPhase(DonePhase); err != nil {
+		t.Fatal(err)
+	}
+	gs.IgnoreRules = true
+	if err := gs.DrawCard("paris"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetPhase(ActionsPhase); err != nil {
+		t.Fatal(err)
+	}
+	gs.IgnoreRules = false
+
+	rebuilt, err := gs.Journal.rebuild("test", gs.Journal.Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marshalState(t, rebuilt) != marshalState(t, gs) {
+		t.Fatal("Overridden commands and phase changes should replay")
+	}
+}
+
+func TestTurnsWithoutPhase(t *testing.T) {
+	var turn Turn
+	if err := json.Unmarshal([]byte(`{"player": "Will", "drawn_cards": ["paris", "tokyo"]}`), &turn); err != nil {
+		t.Fatal(err)
+	}
+	if turn.Phase != InfectPhase {
+		t.Fatalf("A turn that drew its city cards should be infecting, got %v", turn.Phase)
+	}
+}
@@ -11,8 +11,15 @@ type GameTurns struct {
 }
 
 type Turn struct {
-	Player     *Player     `json:"player"`
-	DrawnCards []*CityCard `json:"drawn_cards"`
+	Player          *Player           `json:"player"`
+	DrawnCards      []*CityCard       `json:"drawn_cards"`
+	Outbreaks       []*OutbreakResult `json:"outbreaks"`
+	Phase           TurnPhase         `json:"phase"`
+	ActionsUsed     int               `json:"actions_used"`
+	EpidemicsDrawn  int               `json:"epidemics_drawn"`
+	InfectionsDrawn int               `json:"infections_drawn"`
+	playerID        string
+	drawnIDs        []CardName
 }
 
 func (t *GameTurns) AddPlayer(p *Player) error {
@@ -31,6 +38,15 @@ func (t *GameTurns) AddPlayer(p *Player) error {
 	return nil
 }
 
+func (t *GameTurns) GetPlayer(name string) (*Player, error) {
+	for _, player := range t.PlayerOrder {
+		if player.HumanName == name {
+			return player, nil
+		}
+	}
+	return nil, fmt.Errorf("No player named %v", name)
+}
+
 func (t *GameTurns) RemainingTurnsFor(remainingCityCards int, name string) int {
 	index := -1
 	for i, player := range t.PlayerOrder {
@@ -81,6 +97,7 @@ func (t *GameTurns) addTurn() *Turn {
 	return &Turn{
 		Player:     t.PlayerOrder[t.CurTurn%len(t.PlayerOrder)],
 		DrawnCards: []*CityCard{},
+		Phase:      ActionsPhase,
 	}
 }
 
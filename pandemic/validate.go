@@ -0,0 +1,172 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Problem is one mistake in a game file, found at the JSON path of the
+// value, such as cities[3].neighbors[1].
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%v: %v", p.Path, p.Message)
+}
+
+// Problems lists every mistake found in a game file.
+type Problems []Problem
+
+func (p Problems) Error() string {
+	lines := []string{}
+	for _, problem := range p {
+		lines = append(lines, problem.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (p *Problems) add(path string, format string, args ...interface{}) {
+	*p = append(*p, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the problems as an error, or nil if there are none.
+func (p Problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return p
+}
+
+// Validate checks that every city has a unique name and that every
+// neighbor is the exact name of another city that lists the city back.
+func (c Cities) Validate() error {
+	problems := Problems{}
+	byName := map[CityName]*City{}
+	for i, city := range c {
+		path := fmt.Sprintf("cities[%d]", i)
+		if city.Name == "" {
+			problems.add(path+".name", "Every city must have a name")
+			continue
+		}
+		if _, ok := byName[city.Name]; ok {
+			problems.add(path+".name", "%v is defined more than once", city.Name)
+			continue
+		}
+		byName[city.Name] = city
+	}
+	for i, city := range c {
+		seen := map[string]bool{}
+		for j, neighbor := range city.Neighbors {
+			path := fmt.Sprintf("cities[%d].neighbors[%d]", i, j)
+			other, ok := byName[CityName(neighbor)]
+			switch {
+			case seen[neighbor]:
+				problems.add(path, "%v lists %v more than once", city.Name, neighbor)
+			case CityName(neighbor) == city.Name:
+				problems.add(path, "%v cannot be its own neighbor", city.Name)
+			case !ok:
+				problems.add(path, "%v is not a city%v", neighbor, c.suggest(neighbor))
+			case !other.IsNeighbor(city.Name):
+				problems.add(path, "%v lists %v as a neighbor, but %v does not list %v", city.Name, neighbor, neighbor, city.Name)
+			}
+			seen[neighbor] = true
+		}
+	}
+	return problems.err()
+}
+
+// suggest names the city a misspelled name most likely means, if exactly
+// one city starts with it or it starts with exactly one city.
+func (c Cities) suggest(name string) string {
+	var match CityName
+	for _, city := range c {
+		lower, cn := strings.ToLower(name), strings.ToLower(string(city.Name))
+		if cn == "" || !(strings.HasPrefix(cn, lower) || strings.HasPrefix(lower, cn)) {
+			continue
+		}
+		if match != "" {
+			return ""
+		}
+		match = city.Name
+	}
+	if match == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %v?", match)
+}
+
+// Validate checks a new game file: the board, the diseases of the cities,
+// the players' locations, start cards and characters, the number of
+// epidemics, the funded events and the research stations.
+func (s *NewGameSettings) Validate() error {
+	problems := Problems{}
+	if err := s.Cities.Validate(); err != nil {
+		problems = append(problems, err.(Problems)...)
+	}
+
+	diseases := s.Diseases
+	if len(diseases) == 0 {
+		diseases = DefaultDiseases()
+	}
+	if err := diseases.Validate(Cities{}); err != nil {
+		problems.add("diseases", "%v", err)
+	}
+	for i, city := range s.Cities {
+		if !diseases.Contains(city.Disease) {
+			problems.add(fmt.Sprintf("cities[%d].disease", i), "%v is not a disease of this game", city.Disease)
+		}
+		if city.OriginalDisease != "" && !diseases.Contains(city.OriginalDisease) {
+			problems.add(fmt.Sprintf("cities[%d].original_disease", i), "%v is not a disease of this game", city.OriginalDisease)
+		}
+	}
+
+	startCards := 0
+	for i, player := range s.Players {
+		path := fmt.Sprintf("players[%d]", i)
+		if _, err := s.Cities.GetCity(player.Location); err != nil && player.Location != "" {
+			problems.add(path+".location", "%v is not a city", player.Location)
+		}
+		for j, card := range player.StartCards {
+			if _, err := s.Cities.GetCity(CityName(card)); err != nil {
+				problems.add(fmt.Sprintf("%v.start_cards[%d]", path, j), "%v is not a city", card)
+			}
+		}
+		startCards += len(player.StartCards)
+		if player.Character != nil && !player.Character.Type.IsValid() {
+			problems.add(path+".character.type", "%v is not a character", player.Character.Type)
+		}
+	}
+
+	deckSize := len(s.Cities) + len(s.FundedEvents) - startCards
+	if s.EpidemicsPerGame < 0 {
+		problems.add("epidemicspergame", "The number of epidemics cannot be negative")
+	} else if s.EpidemicsPerGame > deckSize {
+		problems.add("epidemicspergame", "%v epidemics do not fit a deck of %v cards", s.EpidemicsPerGame, deckSize)
+	}
+	if err := s.ValidateFunding(); err != nil {
+		problems.add("funded_events", "%v", err)
+	}
+	if err := s.validateStations(); err != nil {
+		problems.add("cities", "%v", err)
+	}
+	return problems.err()
+}
+
+// ValidateNewGameFile reads and checks a new game file, returning the
+// warnings that do not stop a game from starting along with the problems
+// that do.
+func ValidateNewGameFile(newGameFile string) ([]string, error) {
+	data, err := ioutil.ReadFile(newGameFile)
+	if err != nil {
+		return nil, err
+	}
+	var settings NewGameSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("Invalid new game JSON: %v", err)
+	}
+	return settings.FundingWarnings(), settings.Validate()
+}
@@ -0,0 +1,59 @@
+package pandemic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func problemPaths(err error) []string {
+	paths := []string{}
+	if problems, ok := err.(Problems); ok {
+		for _, problem := range problems {
+			paths = append(paths, problem.Path)
+		}
+	}
+	return paths
+}
+
+func TestValidateDataFiles(t *testing.T) {
+	for _, file := range []string{"../data/pandemicboard.json", "../data/new_game.json", "../data/legacy1.json"} {
+		warnings, err := ValidateNewGameFile(file)
+		if err != nil {
+			t.Fatalf("%v should be valid: %v", file, err)
+		}
+		if len(warnings) > 0 {
+			t.Fatalf("%v should have no warnings, got %v", file, warnings)
+		}
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	settings := boardSettings(t)
+	sanFrancisco := settings.Cities[0]
+	sanFrancisco.Neighbors[0] = "losangele"
+	sanFrancisco.Disease = "Purple"
+	settings.Cities[1].Neighbors = append(settings.Cities[1].Neighbors, "tokyo")
+	settings.Players[0].Location = "gotham"
+	settings.Players[1].Character.Type = "Wizard"
+	settings.EpidemicsPerGame = 99
+
+	err := settings.Validate()
+	expected := []string{
+		"cities[0].neighbors[0]",
+		"cities[1].neighbors[4]",
+		"cities[12].neighbors[0]",
+		"cities[0].disease",
+		"players[0].location",
+		"players[1].character.type",
+		"epidemicspergame",
+	}
+	if !reflect.DeepEqual(problemPaths(err), expected) {
+		t.Fatalf("Expected problems at %v, got:\n%v", expected, err)
+	}
+	if problem := err.(Problems)[0]; problem.Message != "losangele is not a city, did you mean losangeles?" {
+		t.Fatalf("Expected a suggestion for the misspelled neighbor, got %v", problem)
+	}
+	if _, err := NewGameFromSettings(settings, "test"); err == nil {
+		t.Fatal("Invalid settings should not start a game")
+	}
+}
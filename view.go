@@ -20,6 +20,10 @@ type PandemicView struct {
 	colorHighlight      func(string, ...interface{}) string
 	colorOhFuck         func(string, ...interface{}) string
 	fileSaveCounter     int
+	replay              *pandemic.Replay
+	autosaveFile        string
+	campaign            *pandemic.Campaign
+	campaignFile        string
 }
 
 func NewView(logger *logrus.Logger) *PandemicView {
@@ -41,7 +45,9 @@ func (p *PandemicView) Start(game *pandemic.GameState, gui *gocui.Gui) {
 		p.renderCommandsView(game, gui, width)
 		p.renderStriations(game, gui, 2, height/2, width)
 		p.renderCityDeckAndTurns(game, gui, 0, height/2, width/2, height)
-		p.renderConsoleArea(game, gui, width/2, height/2, width, height)
+		p.renderStatus(game, gui, width/2, height/2, width, height/2+statusHeight)
+		p.renderChainRisk(game, gui, width/2, height/2+statusHeight, width, height/2+statusHeight+chainRiskHeight)
+		p.renderConsoleArea(game, gui, width/2, height/2+statusHeight+chainRiskHeight, width, height)
 
 		p.setUpKeyBindings(game, gui, "Commands")
 		gui.Cursor = true
@@ -92,11 +98,11 @@ func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *goc
 
 	fmt.Fprintf(cityView, "Upcoming Draws Guaranteed Safe: %v\n", p.colorUpcomingSafeCount(analysis.ComingDrawsWith0))
 
-	fmt.Fprintf(cityView, "Card counts %v  %v  ", p.iconFor(pandemic.Black.Type), game.CityDeck.RemainingCardsWith(pandemic.Black.Type, game.Cities))
-	fmt.Fprintf(cityView, "%v  %v  ", p.iconFor(pandemic.Red.Type), game.CityDeck.RemainingCardsWith(pandemic.Red.Type, game.Cities))
-	fmt.Fprintf(cityView, "%v  %v  ", p.iconFor(pandemic.Blue.Type), game.CityDeck.RemainingCardsWith(pandemic.Blue.Type, game.Cities))
-	fmt.Fprintf(cityView, "%v  %v  ", p.iconFor(pandemic.Yellow.Type), game.CityDeck.RemainingCardsWith(pandemic.Yellow.Type, game.Cities))
-	fmt.Fprintf(cityView, "%v  %v\n", p.iconFor(pandemic.Faded.Type), game.CityDeck.RemainingCardsWith(pandemic.Faded.Type, game.Cities))
+	fmt.Fprint(cityView, "Card counts")
+	for _, data := range game.DiseaseData {
+		fmt.Fprintf(cityView, " %v  %v ", p.iconFor(game, data.Type), game.CityDeck.RemainingCardsWith(data.Type, game.Cities))
+	}
+	fmt.Fprintln(cityView)
 	fmt.Fprintf(cityView, "Outbreaks \U0001F4A5  %v\n", game.Outbreaks)
 
 	turnView, err := gui.SetView("Turns", topX, topY+(bottomY-topY)/2, bottomX, bottomY)
@@ -114,7 +120,11 @@ func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *goc
 	}
 	for _, player := range game.GameTurns.PlayerOrder {
 		if cur.Player == player {
-			fmt.Fprint(turnView, p.colorWhiteHighlight(fmt.Sprintf("%v (%v)", player.HumanName, player.Character.Type)))
+			characterType := pandemic.CharacterType("no character")
+			if player.Character != nil {
+				characterType = player.Character.Type
+			}
+			fmt.Fprint(turnView, p.colorWhiteHighlight(fmt.Sprintf("%v (%v)", player.HumanName, characterType)))
 		} else {
 			fmt.Fprint(turnView, player.HumanName[:1])
 		}
@@ -122,16 +132,34 @@ func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *goc
 	}
 	fmt.Fprintln(turnView)
 	fmt.Fprintf(turnView, "%v [%v] has %v turns left\n", cur.Player.HumanName, cur.Player.Location, game.GameTurns.RemainingTurnsFor(game.CityDeck.RemainingCards(), cur.Player.HumanName))
+	fmt.Fprintln(turnView, p.colorHighlight(game.PhaseStatus()))
 	if cur.Player.Character != nil && cur.Player.Character.TurnMessage != "" {
 		fmt.Fprintln(turnView, p.colorAllGood(cur.Player.Character.TurnMessage))
 	}
+	abilities := cur.Player.Abilities()
+	handLimit := fmt.Sprintf("%v/%v cards", len(cur.Player.Cards), game.HandLimitOf(cur.Player))
+	if len(cur.Player.Cards) > game.HandLimitOf(cur.Player) {
+		handLimit = p.colorOhFuck(handLimit)
+	}
+	fmt.Fprintf(turnView, "%v actions, %v\n", abilities.ActionsPerTurn(), handLimit)
+	if modifier := game.CureModifierOf(cur.Player); modifier.CannotCure {
+		fmt.Fprintln(turnView, p.colorWarning("Cannot cure diseases"))
+	} else if modifier.Cards != 0 {
+		fmt.Fprintf(turnView, "Cures with %+d cards\n", modifier.Cards)
+	}
+	for _, ability := range abilities.List() {
+		fmt.Fprintln(turnView, p.colorAllGood(ability.Description()))
+	}
+	for _, outbreak := range cur.Outbreaks {
+		fmt.Fprintln(turnView, p.colorOhFuck("\U0001F4A5  %v: %v outbreaks, %v infected, %v quarantines lost", outbreak.Origin, outbreak.Outbreaks(), len(outbreak.Infected), len(outbreak.QuarantinesConsumed)))
+	}
 
 	// print all cards
 	fmt.Fprintf(turnView, "Cards (%v):", len(cur.Player.Cards))
 	for _, card := range cur.Player.Cards {
 		if card.IsCity() {
-			city, _ := game.Cities.GetCity(card.CityName)
-			fmt.Fprintf(turnView, "%v  %v ", p.iconFor(city.Disease), card.CityName[:4])
+			city, _ := game.GetCity(card.CityName)
+			fmt.Fprintf(turnView, "%v  %v ", p.iconFor(game, city.Disease), card.CityName[:4])
 		} else if card.IsFundedEvent() {
 			fmt.Fprintf(turnView, "\U0001F4B8  %v ", card.FundedEventName)
 		}
@@ -139,8 +167,14 @@ func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *goc
 	fmt.Fprintln(turnView, "\nCure Likelihood: ")
 
 	// print curability stats
-	curability := byCurability{pandemic.CurableDiseases(), make(map[pandemic.DiseaseType]float64), make(map[pandemic.DiseaseType]maxCurability)}
-	for _, dt := range pandemic.CurableDiseases() {
+	uncured := []pandemic.DiseaseType{}
+	for _, dt := range game.CurableDiseases() {
+		if !game.IsCured(dt) {
+			uncured = append(uncured, dt)
+		}
+	}
+	curability := byCurability{uncured, make(map[pandemic.DiseaseType]float64), make(map[pandemic.DiseaseType]maxCurability)}
+	for _, dt := range uncured {
 		playerProb := game.ProbabilityOfCuring(cur.Player, dt)
 		curability.curability[dt] = playerProb
 		curability.maxCurability[dt] = maxCurability{playerProb, cur.Player}
@@ -161,27 +195,30 @@ func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *goc
 		if max.player.HumanName != cur.Player.HumanName {
 			maxStr = fmt.Sprintf("(%v %v)", max.player.HumanName, p.colorProbabilityOfCure(max.prob))
 		}
-		fmt.Fprintf(turnView, "%v  \U00002697  %v %v \n", p.iconFor(dt), p.colorProbabilityOfCure(curability.curability[dt]), maxStr)
+		required := "-"
+		if cards, err := game.CardsToCure(cur.Player, dt); err == nil {
+			required = fmt.Sprintf("%v", cards)
+		}
+		fmt.Fprintf(turnView, "%v  \U00002697 %v  %v %v \n", p.iconFor(game, dt), required, p.colorProbabilityOfCure(curability.curability[dt]), maxStr)
 	}
+	for _, dt := range game.CurableDiseases() {
+		if game.IsCured(dt) {
+			fmt.Fprintf(turnView, "%v  \U00002697  %v\n", p.iconFor(game, dt), p.colorAllGood(string(game.CureStatus(dt))))
+		}
+	}
+}
+
+func (p *PandemicView) iconFor(game *pandemic.GameState, dt pandemic.DiseaseType) string {
+	return game.DataForDisease(dt).DisplayIcon()
 }
 
-func (p *PandemicView) iconFor(dt pandemic.DiseaseType) string {
-	var diseaseEmoji string
-	switch dt {
-	case pandemic.Yellow.Type:
-		diseaseEmoji = "\U0001f49b"
-	case pandemic.Blue.Type:
-		diseaseEmoji = "\U0001f499"
-	case pandemic.Red.Type:
-		diseaseEmoji = "\U0001F6D1"
-	case pandemic.Black.Type:
-		diseaseEmoji = "\u26ab"
-	case pandemic.Faded.Type:
-		diseaseEmoji = "\U0001f608"
-	default:
-		diseaseEmoji = string(dt)
-	}
-	return diseaseEmoji
+// panicFor shows one mark per level on the panic track, and a skull once
+// the city has fallen.
+func (p *PandemicView) panicFor(level pandemic.PanicLevel) string {
+	if level == pandemic.Fallen {
+		return "\u2620"
+	}
+	return strings.Repeat("!", int(level))
 }
 
 func (p *PandemicView) colorUpcomingSafeCount(safe int) string {
@@ -226,10 +263,12 @@ func (p *PandemicView) terminateIfErr(err error, msg string, gui *gocui.Gui) {
 
 func (p *PandemicView) setUpKeyBindings(game *pandemic.GameState, gui *gocui.Gui, commandView string) {
 	err := gui.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
-		// when we get a ctrl-C we exit the game
-		gui.Close()
-		p.logger.Fatalf("Buh bye") // TODO: save
-		return nil
+		// when we get a ctrl-C we save and exit the game
+		if err := p.autosave(game); err != nil {
+			p.logger.Errorf("Could not save before exiting: %v", err)
+		}
+		p.logger.Infoln("Buh bye")
+		return gocui.ErrQuit
 	})
 	p.terminateIfErr(err, "could not establish graceful termination keybinding", gui)
 	err = gui.SetKeybinding(commandView, gocui.KeyEnter, gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
@@ -248,7 +287,7 @@ func (p *PandemicView) setUpKeyBindings(game *pandemic.GameState, gui *gocui.Gui
 		}
 		words := strings.Split(cleanBuffer, " ")
 		prefix := words[len(words)-1]
-		city, err := game.Cities.GetCityByPrefix(prefix)
+		city, err := game.Board().CityByPrefix(prefix)
 		if err != nil {
 			return nil
 		}
@@ -262,6 +301,90 @@ func (p *PandemicView) setUpKeyBindings(game *pandemic.GameState, gui *gocui.Gui
 	p.terminateIfErr(err, "could not establish keybinding for command view", gui)
 }
 
+// statusHeight is the height of the status panel above the console.
+const statusHeight = 5
+
+// renderStatus shows the cubes left of every disease, the outbreak track and
+// the city cards left, highlighting anything that loses or is about to
+// lose the game.
+func (p *PandemicView) renderStatus(game *pandemic.GameState, gui *gocui.Gui, topX, topY, bottomX, bottomY int) {
+	view, err := gui.SetView("Status", topX, topY, bottomX, bottomY)
+	if err != nil && err != gocui.ErrUnknownView {
+		gui.Close()
+		p.logger.Fatalf("Could not render status view: %v", err)
+	}
+	view.Clear()
+	view.Title = "Status"
+
+	for _, dt := range game.DiseaseSupply.Diseases() {
+		fmt.Fprintf(view, "%v  %v  ", p.iconFor(game, dt), p.colorCubesLeft(game.DiseaseSupply[dt]))
+	}
+	fmt.Fprintln(view)
+	outbreaks := fmt.Sprintf("%v/%v", game.Outbreaks, pandemic.MaxOutbreaks)
+	if game.Outbreaks >= pandemic.MaxOutbreaks-1 {
+		outbreaks = p.colorOhFuck(outbreaks)
+	} else if game.Outbreaks >= pandemic.MaxOutbreaks/2 {
+		outbreaks = p.colorWarning(outbreaks)
+	}
+	fmt.Fprintf(view, "Outbreaks \U0001F4A5  %v  City cards left %v  Stations \U0001F3E5  %v/%v\n", outbreaks, game.CityDeck.RemainingCards(), len(game.Cities.WithStation()), game.StationSupply())
+
+	if lost := game.LossConditions(); len(lost) > 0 {
+		fmt.Fprintln(view, p.colorOhFuck("LOST: %v", strings.Join(lost, ", ")))
+	} else if warnings := game.LossWarnings(); len(warnings) > 0 {
+		fmt.Fprintln(view, p.colorWarning("%v", strings.Join(warnings, ", ")))
+	}
+}
+
+// chainRiskHeight is the height of the chain risk panel above the console.
+const chainRiskHeight = 7
+
+// renderChainRisk lists the clusters of cities most likely to outbreak
+// together during the coming turn, highlighting cascades that would reach
+// the outbreak that loses the game.
+func (p *PandemicView) renderChainRisk(game *pandemic.GameState, gui *gocui.Gui, topX, topY, bottomX, bottomY int) {
+	view, err := gui.SetView("Chains", topX, topY, bottomX, bottomY)
+	if err != nil && err != gocui.ErrUnknownView {
+		gui.Close()
+		p.logger.Fatalf("Could not render chain risk view: %v", err)
+	}
+	view.Clear()
+	view.Title = "Chain Risk"
+
+	clusters := game.ChainClusters()
+	if len(clusters) == 0 {
+		fmt.Fprintln(view, p.colorAllGood("No outbreaks possible this turn"))
+		return
+	}
+	for i, cluster := range clusters {
+		if i == chainRiskHeight-2 {
+			break
+		}
+		cities := []string{}
+		for _, cn := range cluster.Cities {
+			cities = append(cities, string(cn))
+		}
+		text := fmt.Sprintf("%v  %.2f  💥 %.2f (up to %v)  +%.1f cubes  %v", p.iconFor(game, cluster.Disease), cluster.Probability,
+			cluster.ExpectedOutbreaks, cluster.Outbreaks, cluster.ExpectedCubes, strings.Join(cities, " "))
+		if game.Outbreaks+cluster.Outbreaks >= pandemic.MaxOutbreaks {
+			fmt.Fprintln(view, p.colorOhFuck(text))
+		} else if cluster.Outbreaks > 1 {
+			fmt.Fprintln(view, p.colorWarning(text))
+		} else {
+			fmt.Fprintln(view, text)
+		}
+	}
+}
+
+func (p *PandemicView) colorCubesLeft(cubes int) string {
+	if cubes > 8 {
+		return p.colorAllGood(fmt.Sprintf("%v", cubes))
+	} else if cubes > 3 {
+		return p.colorWarning(fmt.Sprintf("%v", cubes))
+	} else {
+		return p.colorOhFuck(fmt.Sprintf("%v", cubes))
+	}
+}
+
 func (p *PandemicView) renderConsoleArea(game *pandemic.GameState, gui *gocui.Gui, topX, topY, bottomX, bottomY int) {
 	view, err := gui.SetView("Console", topX, topY, bottomX, bottomY)
 	view.Title = "Console"
@@ -271,6 +394,9 @@ func (p *PandemicView) renderConsoleArea(game *pandemic.GameState, gui *gocui.Gu
 	if err == gocui.ErrUnknownView {
 		fmt.Fprintf(view, "~ %v %v %v ~\n", p.colorAllGood("Pandemic Legacy"), p.colorHighlight("NeRd hUrD"), p.colorWarning("Assist-o-tron"))
 		fmt.Fprintf(view, "Starting %v, %v City Cards, %v Epidemics, %v Funded Events\n", game.GameName, game.CityDeck.Total(), game.CityDeck.NumEpidemics(), game.CityDeck.NumFundedEvents())
+		if p.replay != nil {
+			fmt.Fprintf(view, "Replaying %v events, step[n] to advance one turn\n", p.replay.Remaining())
+		}
 	}
 }
 
@@ -316,25 +442,26 @@ func (p *PandemicView) printCityWithProb(game *pandemic.GameState, view *gocui.V
 	if err != nil {
 		return err
 	}
-	// diseaseData, err := game.GetDiseaseData(cityData.Disease)
-	// if err != nil {
-	// 	return err
-	// }
 	probability := game.ProbabilityOfCity(city)
 
-	diseaseEmoji := p.iconFor(cityData.Disease)
+	diseaseEmoji := p.iconFor(game, cityData.Disease)
 
-	infectionRateEmojis := ""
-	for i := 0; i < cityData.NumInfections; i++ {
-		infectionRateEmojis += "•"
+	infectionRateEmojis := strings.Repeat("•", cityData.CubesOf(cityData.Disease))
+	for _, data := range game.DiseaseData {
+		if cubes := cityData.CubesOf(data.Type); cubes > 0 && data.Type != cityData.Disease {
+			infectionRateEmojis += fmt.Sprintf(" %v%v", p.iconFor(game, data.Type), cubes)
+		}
 	}
 
 	quarantinedEmoji := ""
 	if cityData.Quarantined {
 		quarantinedEmoji = "\u26d4"
 	}
+	if cityData.ResearchStation {
+		quarantinedEmoji += "\U0001F3E5"
+	}
 
-	text := fmt.Sprintf("%v %s  %s  %s  %.2f", city[:4], diseaseEmoji, infectionRateEmojis, quarantinedEmoji, probability)
+	text := fmt.Sprintf("%v %s  %s  %s %s  %.2f", city[:4], diseaseEmoji, infectionRateEmojis, quarantinedEmoji, p.panicFor(cityData.PanicLevel), probability)
 	if probability == 0.0 {
 		fmt.Fprintln(view, p.colorAllGood(text))
 	} else if game.CanOutbreak(city) {